@@ -68,7 +68,13 @@ module Provider
                           ['google/provider_handwritten_endpoint.go',
                            'third_party/terraform/utils/provider_handwritten_endpoint.go.erb'],
                           ['google/transport.go',
-                           'third_party/terraform/utils/transport.go.erb']
+                           'third_party/terraform/utils/transport.go.erb'],
+                          ['google/iam_service_account.go',
+                           'third_party/terraform/utils/iam_service_account.go.erb'],
+                          ['google/iam_kms_key_ring.go',
+                           'third_party/terraform/utils/iam_kms_key_ring.go.erb'],
+                          ['google/iam_kms_crypto_key.go',
+                           'third_party/terraform/utils/iam_kms_crypto_key.go.erb']
                         ],
                         file_template)
     end
@@ -84,6 +90,20 @@ module Provider
                         'third_party/validator/cai.go'],
                        ['google/cai_test.go',
                         'third_party/validator/cai_test.go'],
+                       ['google/ancestry.go',
+                        'third_party/validator/ancestry.go'],
+                       ['google/ancestry_test.go',
+                        'third_party/validator/ancestry_test.go'],
+                       ['google/cai_to_terraform.go',
+                        'third_party/validator/cai_to_terraform.go'],
+                       ['google/cai_to_terraform_test.go',
+                        'third_party/validator/cai_to_terraform_test.go'],
+                       ['google/discovery_conformance.go',
+                        'third_party/validator/discovery_conformance.go'],
+                       ['google/discovery_conformance_test.go',
+                        'third_party/validator/discovery_conformance_test.go'],
+                       ['google/testdata/discovery',
+                        'third_party/validator/testdata/discovery'],
                        ['google/json_map.go',
                         'third_party/validator/json_map.go'],
                        ['google/project.go',
@@ -96,6 +116,28 @@ module Provider
                         'third_party/validator/storage_bucket.go'],
                        ['google/storage_bucket_iam.go',
                         'third_party/validator/storage_bucket_iam.go'],
+                       ['google/service_account_iam.go',
+                        'third_party/validator/service_account_iam.go'],
+                       ['google/kms_key_ring_iam.go',
+                        'third_party/validator/kms_key_ring_iam.go'],
+                       ['google/kms_crypto_key_iam.go',
+                        'third_party/validator/kms_crypto_key_iam.go'],
+                       ['google/pubsub_subscription_iam.go',
+                        'third_party/validator/pubsub_subscription_iam.go'],
+                       ['google/spanner_instance_iam.go',
+                        'third_party/validator/spanner_instance_iam.go'],
+                       ['google/spanner_database_iam.go',
+                        'third_party/validator/spanner_database_iam.go'],
+                       ['google/bigquery_dataset_iam.go',
+                        'third_party/validator/bigquery_dataset_iam.go'],
+                       ['google/healthcare_dataset_iam.go',
+                        'third_party/validator/healthcare_dataset_iam.go'],
+                       ['google/healthcare_dicom_store_iam.go',
+                        'third_party/validator/healthcare_dicom_store_iam.go'],
+                       ['google/healthcare_fhir_store_iam.go',
+                        'third_party/validator/healthcare_fhir_store_iam.go'],
+                       ['google/healthcare_hl7_v2_store_iam.go',
+                        'third_party/validator/healthcare_hl7_v2_store_iam.go'],
                        ['google/cloudfunctions_function.go',
                         'third_party/validator/cloudfunctions_function.go'],
                        ['google/bigquery_table.go',
@@ -108,12 +150,20 @@ module Provider
                         'third_party/validator/iam_helpers.go'],
                        ['google/iam_helpers_test.go',
                         'third_party/validator/iam_helpers_test.go'],
+                       ['google/iam_converters_test.go',
+                        'third_party/validator/iam_converters_test.go'],
                        ['google/organization_iam.go',
                         'third_party/validator/organization_iam.go'],
                        ['google/project_iam.go',
                         'third_party/validator/project_iam.go'],
                        ['google/project_organization_policy.go',
                         'third_party/validator/project_organization_policy.go'],
+                       ['google/folder_organization_policy.go',
+                        'third_party/validator/folder_organization_policy.go'],
+                       ['google/organization_policy.go',
+                        'third_party/validator/organization_policy.go'],
+                       ['google/organization_policy_test.go',
+                        'third_party/validator/organization_policy_test.go'],
                        ['google/folder_iam.go',
                         'third_party/validator/folder_iam.go'],
                        ['google/container.go',
@@ -160,12 +210,18 @@ module Provider
                         'third_party/terraform/utils/source_repo_utils.go'],
                        ['google/retry_transport.go',
                         'third_party/terraform/utils/retry_transport.go'],
+                       ['google/rate_limit_transport.go',
+                        'third_party/terraform/utils/rate_limit_transport.go'],
+                       ['google/trace_transport.go',
+                        'third_party/terraform/utils/trace_transport.go'],
                        ['google/error_retry_predicates.go',
                         'third_party/terraform/utils/error_retry_predicates.go'],
                        ['google/pubsub_utils.go',
                         'third_party/terraform/utils/pubsub_utils.go'],
                        ['google/sqladmin_operation.go',
                         'third_party/terraform/utils/sqladmin_operation.go'],
+                       ['google/operation_shape.go',
+                        'third_party/terraform/utils/operation_shape.go'],
                        ['google/path_or_contents.go',
                         'third_party/terraform/utils/path_or_contents.go'],
                        ['google/mutexkv.go',
@@ -177,7 +233,25 @@ module Provider
                        ['google/iam_folder.go',
                         'third_party/terraform/utils/iam_folder.go'],
                        ['google/iam_project.go',
-                        'third_party/terraform/utils/iam_project.go']
+                        'third_party/terraform/utils/iam_project.go'],
+                       ['google/iam_pubsub_subscription.go',
+                        'third_party/terraform/utils/iam_pubsub_subscription.go'],
+                       ['google/iam_spanner_instance.go',
+                        'third_party/terraform/utils/iam_spanner_instance.go'],
+                       ['google/iam_spanner_database.go',
+                        'third_party/terraform/utils/iam_spanner_database.go'],
+                       ['google/iam_bigquery_dataset.go',
+                        'third_party/terraform/utils/iam_bigquery_dataset.go'],
+                       ['google/iam_healthcare_dataset.go',
+                        'third_party/terraform/utils/iam_healthcare_dataset.go'],
+                       ['google/iam_healthcare_dicom_store.go',
+                        'third_party/terraform/utils/iam_healthcare_dicom_store.go'],
+                       ['google/iam_healthcare_fhir_store.go',
+                        'third_party/terraform/utils/iam_healthcare_fhir_store.go'],
+                       ['google/iam_healthcare_hl7_v2_store.go',
+                        'third_party/terraform/utils/iam_healthcare_hl7_v2_store.go'],
+                       ['google/healthcare_utils.go',
+                        'third_party/terraform/utils/healthcare_utils.go']
                      ])
     end
 
@@ -0,0 +1,200 @@
+// vcrcassettes reports stale and duplicate VCR cassettes in a cassette directory.
+//
+// Example usage: go run vcrcassettes.go -path $VCR_PATH
+//
+// A cassette is stale if no acceptance test in the provider records it, which
+// happens when tests are renamed or removed. Seed files without a cassette are
+// reported as stale too. Cassettes are duplicates of one another if they
+// record the same requests.
+//
+// It exits with a non-zero status if any stale or duplicate cassettes are found.
+
+package main
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/dnaeon/go-vcr/cassette"
+)
+
+func main() {
+	path := flag.String("path", os.Getenv("VCR_PATH"), "directory containing VCR cassettes, defaults to $VCR_PATH")
+	flag.Parse()
+	if *path == "" {
+		fmt.Println("-path must be set")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	_, scriptPath, _, ok := runtime.Caller(0)
+	if !ok {
+		log.Fatal("Could not get current working directory")
+	}
+	tpgDir := scriptPath
+	for !strings.HasPrefix(filepath.Base(tpgDir), "terraform-provider-") && tpgDir != "/" {
+		tpgDir = filepath.Clean(tpgDir + "/..")
+	}
+	if tpgDir == "/" {
+		log.Fatal("Script was run outside of google provider directory")
+	}
+	repo := strings.TrimPrefix(filepath.Base(tpgDir), "terraform-provider-")
+	googleDir := tpgDir + "/" + repo
+
+	tests, err := readTestNames(googleDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cassettes, seeds, err := readCassetteDir(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stale := staleCassettes(cassettes, seeds, tests)
+	for _, name := range stale {
+		fmt.Printf("stale: %s\n", name)
+	}
+
+	duplicates, err := duplicateCassettes(*path, cassettes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, names := range duplicates {
+		fmt.Printf("duplicate: %s\n", strings.Join(names, ", "))
+	}
+
+	if len(stale) > 0 || len(duplicates) > 0 {
+		os.Exit(1)
+	}
+}
+
+// readTestNames returns the names of the test functions in googleDir.
+func readTestNames(googleDir string) (map[string]struct{}, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, googleDir, func(fi os.FileInfo) bool {
+		return strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tests := map[string]struct{}{}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && strings.HasPrefix(fd.Name.Name, "Test") {
+					tests[fd.Name.Name] = struct{}{}
+				}
+			}
+		}
+	}
+	return tests, nil
+}
+
+// readCassetteDir returns the names of the cassettes and seed files in dir,
+// without their extensions.
+func readCassetteDir(dir string) ([]string, []string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cassettes, seeds []string
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		switch filepath.Ext(f.Name()) {
+		case ".yaml":
+			cassettes = append(cassettes, strings.TrimSuffix(f.Name(), ".yaml"))
+		case ".seed":
+			seeds = append(seeds, strings.TrimSuffix(f.Name(), ".seed"))
+		}
+	}
+	return cassettes, seeds, nil
+}
+
+// staleCassettes returns the files for cassettes that no test records, and
+// for seeds without a cassette. Cassettes of subtests are named after their
+// test, followed by "_" and the name of the subtest.
+func staleCassettes(cassettes, seeds []string, tests map[string]struct{}) []string {
+	var stale []string
+	hasCassette := map[string]struct{}{}
+	for _, name := range cassettes {
+		hasCassette[name] = struct{}{}
+		if !isRecordedByTest(name, tests) {
+			stale = append(stale, name+".yaml")
+		}
+	}
+	for _, name := range seeds {
+		if _, ok := hasCassette[name]; !ok {
+			stale = append(stale, name+".seed")
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func isRecordedByTest(name string, tests map[string]struct{}) bool {
+	if _, ok := tests[name]; ok {
+		return true
+	}
+	// Test names may contain "_" too, so try every prefix ending before one.
+	for i := strings.Index(name, "_"); i >= 0; {
+		if _, ok := tests[name[:i]]; ok {
+			return true
+		}
+		next := strings.Index(name[i+1:], "_")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
+
+// duplicateCassettes returns groups of cassettes that record the same
+// requests, in the same order.
+func duplicateCassettes(dir string, cassettes []string) ([][]string, error) {
+	byRequests := map[[sha256.Size]byte][]string{}
+	for _, name := range cassettes {
+		c, err := cassette.Load(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("error loading cassette %s: %s", name, err)
+		}
+		if len(c.Interactions) == 0 {
+			continue
+		}
+		h := sha256.New()
+		for _, i := range c.Interactions {
+			fmt.Fprintf(h, "%s %s\n%d:%s\n", i.Request.Method, i.Request.URL, len(i.Request.Body), i.Request.Body)
+		}
+		var sum [sha256.Size]byte
+		copy(sum[:], h.Sum(nil))
+		byRequests[sum] = append(byRequests[sum], name+".yaml")
+	}
+
+	var duplicates [][]string
+	for _, names := range byRequests {
+		if len(names) > 1 {
+			sort.Strings(names)
+			duplicates = append(duplicates, names)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i][0] < duplicates[j][0]
+	})
+	return duplicates, nil
+}
@@ -47,7 +47,7 @@ func TestAccKmsSecret_basic(t *testing.T) {
 						Steps: []resource.TestStep{
 							{
 								Config: testGoogleKmsSecret_datasource(cryptoKeyId.terraformId(), ciphertext),
-								Check:  resource.TestCheckResourceAttr("data.google_kms_secret.acceptance", "plaintext", plaintext),
+								Check:  resource.TestCheckResourceAttr("data.google_kms_secret.acceptance", "plaintext", vcrPlaintext(plaintext)),
 							},
 						},
 					})
@@ -72,7 +72,7 @@ func TestAccKmsSecret_basic(t *testing.T) {
 						Steps: []resource.TestStep{
 							{
 								Config: testGoogleKmsSecret_aadDatasource(cryptoKeyId.terraformId(), ciphertext, base64.StdEncoding.EncodeToString([]byte(aad))),
-								Check:  resource.TestCheckResourceAttr("data.google_kms_secret.acceptance", "plaintext", plaintext),
+								Check:  resource.TestCheckResourceAttr("data.google_kms_secret.acceptance", "plaintext", vcrPlaintext(plaintext)),
 							},
 						},
 					})
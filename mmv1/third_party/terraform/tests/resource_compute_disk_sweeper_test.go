@@ -1,7 +1,7 @@
 package google
 
 import (
-	"context"
+	"fmt"
 	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -17,24 +17,16 @@ func init() {
 
 // At the time of writing, the CI only passes us-central1 as the region
 func testSweepDisk(region string) error {
-	resourceName := "ComputeDisk"
-	log.Printf("[INFO][SWEEPER_LOG] Starting sweeper for %s", resourceName)
+	return runSweeper("ComputeDisk", region, sweepDisk)
+}
 
-	config, err := sharedConfigForRegion(region)
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error getting shared config for region: %s", err)
-		return err
-	}
+func sweepDisk(config *Config, run *sweeperRun) error {
+	resourceName := run.Sweeper
 
-	err = config.LoadAndValidate(context.Background())
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error loading: %s", err)
-		return err
-	}
 	servicesUrl := "https://compute.googleapis.com/compute/v1/projects/" + config.Project + "/zones/" + getTestZoneFromEnv() + "/disks"
 	res, err := sendRequest(config, "GET", config.Project, servicesUrl, config.userAgent, nil)
 	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] Error in response from request %s: %s", servicesUrl, err)
+		run.fail(fmt.Errorf("Error in response from request %s: %s", servicesUrl, err))
 		return nil
 	}
 
@@ -47,8 +39,6 @@ func testSweepDisk(region string) error {
 	rl := resourceList.([]interface{})
 
 	log.Printf("[INFO][SWEEPER_LOG] Found %d items in %s list response.", len(rl), resourceName)
-	// Count items that weren't sweeped.
-	nonPrefixCount := 0
 	for _, ri := range rl {
 		obj := ri.(map[string]interface{})
 		if obj["id"] == nil {
@@ -57,24 +47,15 @@ func testSweepDisk(region string) error {
 		}
 
 		id := obj["name"].(string)
-		// Increment count and skip if resource is not sweepable.
-		if !isSweepableTestResource(id) {
-			nonPrefixCount++
+		// Skip resources that shouldn't be sweeped
+		if !run.shouldSweep(id, sweepableResourceCreateTime(obj)) {
 			continue
 		}
 
 		deleteUrl := servicesUrl + "/" + id
 		// Don't wait on operations as we may have a lot to delete
 		_, err = sendRequest(config, "DELETE", config.Project, deleteUrl, config.userAgent, nil)
-		if err != nil {
-			log.Printf("[INFO][SWEEPER_LOG] Error deleting for url %s : %s", deleteUrl, err)
-		} else {
-			log.Printf("[INFO][SWEEPER_LOG] Sent delete request for %s resource: %s", resourceName, id)
-		}
-	}
-
-	if nonPrefixCount > 0 {
-		log.Printf("[INFO][SWEEPER_LOG] %d items without tf-test prefix remain.", nonPrefixCount)
+		run.deleted(id, err)
 	}
 
 	return nil
@@ -1,9 +1,7 @@
 package google
 
 import (
-	"context"
 	"fmt"
-	"log"
 	"regexp"
 	"sort"
 	"strconv"
@@ -27,50 +25,28 @@ func init() {
 // At the time of writing, the CI only passes us-central1 as the region.
 // Since we can read all instances across zones, we don't really use this param.
 func testSweepComputeInstance(region string) error {
-	resourceName := "ComputeInstance"
-	log.Printf("[INFO][SWEEPER_LOG] Starting sweeper for %s", resourceName)
-
-	config, err := sharedConfigForRegion(region)
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error getting shared config for region: %s", err)
-		return err
-	}
-
-	err = config.LoadAndValidate(context.Background())
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error loading: %s", err)
-		return err
-	}
+	return runSweeper("ComputeInstance", region, sweepComputeInstance)
+}
 
+func sweepComputeInstance(config *Config, run *sweeperRun) error {
 	found, err := config.NewComputeClient(config.userAgent).Instances.AggregatedList(config.Project).Do()
 	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] Error in response from request: %s", err)
+		run.fail(fmt.Errorf("Error in response from request: %s", err))
 		return nil
 	}
 
-	// Keep count of items that aren't sweepable for logging.
-	nonPrefixCount := 0
 	for zone, itemList := range found.Items {
 		for _, instance := range itemList.Instances {
-			if !isSweepableTestResource(instance.Name) {
-				nonPrefixCount++
+			if !run.shouldSweep(instance.Name, instance.CreationTimestamp) {
 				continue
 			}
 
 			// Don't wait on operations as we may have a lot to delete
 			_, err := config.NewComputeClient(config.userAgent).Instances.Delete(config.Project, GetResourceNameFromSelfLink(zone), instance.Name).Do()
-			if err != nil {
-				log.Printf("[INFO][SWEEPER_LOG] Error deleting %s resource %s : %s", resourceName, instance.Name, err)
-			} else {
-				log.Printf("[INFO][SWEEPER_LOG] Sent delete request for %s resource: %s", resourceName, instance.Name)
-			}
+			run.deleted(instance.Name, err)
 		}
 	}
 
-	if nonPrefixCount > 0 {
-		log.Printf("[INFO][SWEEPER_LOG] %d items were non-sweepable and skipped.", nonPrefixCount)
-	}
-
 	return nil
 }
 
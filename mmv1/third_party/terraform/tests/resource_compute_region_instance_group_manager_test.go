@@ -1,9 +1,7 @@
 package google
 
 import (
-	"context"
 	"fmt"
-	"log"
 	"strings"
 	"testing"
 
@@ -21,46 +19,24 @@ func init() {
 // At the time of writing, the CI only passes us-central1 as the region.
 // Since we can read all instances across zones, we don't really use this param.
 func testSweepComputeRegionInstanceGroupManager(region string) error {
-	resourceName := "ComputeRegionInstanceGroupManager"
-	log.Printf("[INFO][SWEEPER_LOG] Starting sweeper for %s", resourceName)
-
-	config, err := sharedConfigForRegion(region)
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error getting shared config for region: %s", err)
-		return err
-	}
-
-	err = config.LoadAndValidate(context.Background())
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error loading: %s", err)
-		return err
-	}
+	return runSweeper("ComputeRegionInstanceGroupManager", region, sweepComputeRegionInstanceGroupManager)
+}
 
-	found, err := config.NewComputeClient(config.userAgent).RegionInstanceGroupManagers.List(config.Project, region).Do()
+func sweepComputeRegionInstanceGroupManager(config *Config, run *sweeperRun) error {
+	found, err := config.NewComputeClient(config.userAgent).RegionInstanceGroupManagers.List(config.Project, run.Region).Do()
 	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] Error in response from request: %s", err)
+		run.fail(fmt.Errorf("Error in response from request: %s", err))
 		return nil
 	}
 
-	// Keep count of items that aren't sweepable for logging.
-	nonPrefixCount := 0
 	for _, rigm := range found.Items {
-		if !isSweepableTestResource(rigm.Name) {
-			nonPrefixCount++
+		if !run.shouldSweep(rigm.Name, rigm.CreationTimestamp) {
 			continue
 		}
 
 		// Don't wait on operations as we may have a lot to delete
-		_, err := config.NewComputeClient(config.userAgent).RegionInstanceGroupManagers.Delete(config.Project, region, rigm.Name).Do()
-		if err != nil {
-			log.Printf("[INFO][SWEEPER_LOG] Error deleting %s resource %s : %s", resourceName, rigm.Name, err)
-		} else {
-			log.Printf("[INFO][SWEEPER_LOG] Sent delete request for %s resource: %s", resourceName, rigm.Name)
-		}
-	}
-
-	if nonPrefixCount > 0 {
-		log.Printf("[INFO][SWEEPER_LOG] %d items were non-sweepable and skipped.", nonPrefixCount)
+		_, err := config.NewComputeClient(config.userAgent).RegionInstanceGroupManagers.Delete(config.Project, run.Region, rigm.Name).Do()
+		run.deleted(rigm.Name, err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package google
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -161,7 +162,7 @@ func testAccCheckProjectService(t *testing.T, services []string, pid string, exp
 	return func(s *terraform.State) error {
 		config := googleProviderConfig(t)
 
-		currentlyEnabled, err := listCurrentlyEnabledServices(pid, config.userAgent, config, time.Minute*10)
+		currentlyEnabled, err := listCurrentlyEnabledServices(context.Background(), pid, config.userAgent, config, time.Minute*10)
 		if err != nil {
 			return fmt.Errorf("Error listing services for project %q: %v", pid, err)
 		}
@@ -1,7 +1,6 @@
 package google
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"os"
@@ -30,39 +29,29 @@ func init() {
 }
 
 func testSweepProject(region string) error {
-	config, err := sharedConfigForRegion(region)
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error getting shared config for region: %s", err)
-		return err
-	}
-
-	err = config.LoadAndValidate(context.Background())
-	if err != nil {
-		log.Printf("[INFO][SWEEPER_LOG] error loading: %s", err)
-		return err
-	}
+	return runOrgSweeper("Project", region, sweepProject)
+}
 
+func sweepProject(config *Config, run *sweeperRun) error {
 	token := ""
 	for paginate := true; paginate; {
 		// Filter for projects with test prefix
 		filter := "id:" + testPrefix + "*"
 		found, err := config.NewResourceManagerClient(config.userAgent).Projects.List().Filter(filter).PageToken(token).Do()
 		if err != nil {
-			log.Printf("[INFO][SWEEPER_LOG] error listing projects: %s", err)
+			run.fail(fmt.Errorf("error listing projects: %s", err))
 			return nil
 		}
 		for _, project := range found.Projects {
 			if project.LifecycleState != "ACTIVE" {
 				continue
 			}
-			log.Printf("[INFO][SWEEPER_LOG] Sweeping Project id: %s", project.ProjectId)
-
-			_, err := config.NewResourceManagerClient(config.userAgent).Projects.Delete(project.ProjectId).Do()
-
-			if err != nil {
-				log.Printf("[INFO][SWEEPER_LOG] Error, failed to delete project %s: %s", project.Name, err)
+			if !run.shouldSweep(project.ProjectId, project.CreateTime) {
 				continue
 			}
+
+			_, err := config.NewResourceManagerClient(config.userAgent).Projects.Delete(project.ProjectId).Do()
+			run.deleted(project.ProjectId, err)
 		}
 		token = found.NextPageToken
 		paginate = token != ""
@@ -13,21 +13,18 @@ var (
 	appEngineOperationIdRegexp = regexp.MustCompile(fmt.Sprintf("apps/%s/operations/(.*)", ProjectRegex))
 )
 
-type AppEngineOperationWaiter struct {
-	Service *appengine.APIService
-	AppId   string
-	CommonOperationWaiter
-}
-
-func (w *AppEngineOperationWaiter) QueryOp() (interface{}, error) {
-	if w == nil {
-		return nil, fmt.Errorf("Cannot query operation, it's unset or nil.")
-	}
-	matches := appEngineOperationIdRegexp.FindStringSubmatch(w.Op.Name)
-	if len(matches) != 2 {
-		return nil, fmt.Errorf("Expected %d results of parsing operation name, got %d from %s", 2, len(matches), w.Op.Name)
+func newAppEngineOperationWaiter(config *Config, appId, userAgent string) *ShapedOperationWaiter {
+	service := config.NewAppEngineClient(userAgent)
+	return &ShapedOperationWaiter{
+		Shape: LongRunningOperationShape,
+		QueryF: func(name string) (interface{}, error) {
+			matches := appEngineOperationIdRegexp.FindStringSubmatch(name)
+			if len(matches) != 2 {
+				return nil, fmt.Errorf("Expected %d results of parsing operation name, got %d from %s", 2, len(matches), name)
+			}
+			return service.Apps.Operations.Get(appId, matches[1]).Do()
+		},
 	}
-	return w.Service.Apps.Operations.Get(w.AppId, matches[1]).Do()
 }
 
 func appEngineOperationWaitTimeWithResponse(config *Config, res interface{}, response *map[string]interface{}, appId, activity, userAgent string, timeout time.Duration) error {
@@ -37,18 +34,18 @@ func appEngineOperationWaitTimeWithResponse(config *Config, res interface{}, res
 		return err
 	}
 
-	w := &AppEngineOperationWaiter{
-		Service: config.NewAppEngineClient(userAgent),
-		AppId:   appId,
-	}
-
+	w := newAppEngineOperationWaiter(config, appId, userAgent)
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
 	if err := OperationWait(w, activity, timeout, config.PollInterval); err != nil {
 		return err
 	}
-	return json.Unmarshal([]byte(w.CommonOperationWaiter.Op.Response), response)
+	b, err := w.ResultJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, response)
 }
 
 func appEngineOperationWaitTime(config *Config, res interface{}, appId, activity, userAgent string, timeout time.Duration) error {
@@ -58,11 +55,7 @@ func appEngineOperationWaitTime(config *Config, res interface{}, appId, activity
 		return err
 	}
 
-	w := &AppEngineOperationWaiter{
-		Service: config.NewAppEngineClient(userAgent),
-		AppId:   appId,
-	}
-
+	w := newAppEngineOperationWaiter(config, appId, userAgent)
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/errwrap"
 	"log"
+	"strings"
 	"sync"
 	"time"
 )
@@ -57,8 +58,10 @@ type (
 	// BatcherCombineFunc is a function type for combine existing batches and additional batch data
 	BatcherCombineFunc func(body interface{}, toAdd interface{}) (interface{}, error)
 
-	// BatcherSendFunc is a function type for sending a batch request
-	BatcherSendFunc func(resourceName string, body interface{}) (interface{}, error)
+	// BatcherSendFunc is a function type for sending a batch request. ctx
+	// carries the batch key and debug ids of the batched requests, and should
+	// be used for the HTTP requests made to send the batch where possible.
+	BatcherSendFunc func(ctx context.Context, resourceName string, body interface{}) (interface{}, error)
 )
 
 // batchResponse bundles an API response (data, error) tuple.
@@ -99,6 +102,9 @@ type batchSubscriber struct {
 type batchingConfig struct {
 	sendAfter      time.Duration
 	enableBatching bool
+	// maxBatchSize is the maximum number of requests combined into a batch
+	// before it is sent early, or 0 for no limit.
+	maxBatchSize int
 }
 
 // Initializes a new batcher.
@@ -162,7 +168,7 @@ func (b *RequestBatcher) SendRequestWithTimeout(batchKey string, request *BatchR
 	}
 	if !b.enableBatching {
 		log.Printf("[DEBUG] Batching is disabled, sending single request for %q", request.DebugId)
-		return request.SendF(request.ResourceName, request.Body)
+		return request.SendF(contextWithTraceInfo(context.Background(), batchKey, request.DebugId), request.ResourceName, request.Body)
 	}
 
 	respCh, err := b.registerBatchRequest(batchKey, request)
@@ -214,7 +220,19 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 
 	// If batch already exists, combine this request into existing request.
 	if batch, ok := b.batches[batchKey]; ok {
-		return batch.addRequest(newRequest)
+		respCh, err := batch.addRequest(newRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		// Send full batches early. If the timer has already fired, it is
+		// waiting to pop the batch and will send it instead.
+		if b.maxBatchSize > 0 && len(batch.subscribers) >= b.maxBatchSize && batch.timer.Stop() {
+			log.Printf("[DEBUG] Batch %q reached maximum size %d, sending early", batchKey, b.maxBatchSize)
+			delete(b.batches, batchKey)
+			go b.sendBatchWithBisectingRetry(batchKey, batch)
+		}
+		return respCh, nil
 	}
 
 	// Batch doesn't exist for given batch key - create a new batch.
@@ -229,7 +247,7 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 	}
 
 	// Create a new batch with copy of the given batch request.
-	b.batches[batchKey] = &startedBatch{
+	batch := &startedBatch{
 		BatchRequest: &BatchRequest{
 			ResourceName: newRequest.ResourceName,
 			Body:         newRequest.Body,
@@ -241,42 +259,42 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 		subscribers: []batchSubscriber{sub},
 	}
 
+	// A batch can be full as soon as it's created, as when the maximum size is
+	// 1. Send it right away rather than letting other requests join it.
+	if b.maxBatchSize > 0 && len(batch.subscribers) >= b.maxBatchSize {
+		log.Printf("[DEBUG] Batch %q reached maximum size %d, sending early", batchKey, b.maxBatchSize)
+		go b.sendBatchWithBisectingRetry(batchKey, batch)
+		return respCh, nil
+	}
+	b.batches[batchKey] = batch
+
 	// Start a timer to send the request
-	b.batches[batchKey].timer = time.AfterFunc(b.sendAfter, func() {
+	batch.timer = time.AfterFunc(b.sendAfter, func() {
 		batch := b.popBatch(batchKey)
 		if batch == nil {
 			log.Printf("[ERROR] batch should have been added to saved batches - just run as single request %q", newRequest.DebugId)
-			respCh <- newRequest.send()
+			respCh <- newRequest.send(contextWithTraceInfo(context.Background(), batchKey, newRequest.DebugId))
 			close(respCh)
 		} else {
-			b.sendBatchWithSingleRetry(batchKey, batch)
+			b.sendBatchWithBisectingRetry(batchKey, batch)
 		}
 	})
 
 	return respCh, nil
 }
 
-func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *startedBatch) {
+func (b *RequestBatcher) sendBatchWithBisectingRetry(batchKey string, batch *startedBatch) {
 	log.Printf("[DEBUG] Sending batch %q combining %d requests)", batchKey, len(batch.subscribers))
-	resp := batch.send()
+	resp := batch.send(batchTraceContext(batchKey, batch.subscribers))
 
-	// If the batch failed and combines more than one request, retry each single request.
+	// If the batch failed and combines more than one request, bisect it to
+	// find the failing requests.
 	if resp.IsError() && len(batch.subscribers) > 1 {
 		log.Printf("[DEBUG] Batch failed with error: %v", resp.err)
-		log.Printf("[DEBUG] Sending each request in batch separately")
-		for _, sub := range batch.subscribers {
-			log.Printf("[DEBUG] Retrying single request %q", sub.singleRequest.DebugId)
-			singleResp := sub.singleRequest.send()
-			log.Printf("[DEBUG] Retried single request %q returned response: %v", sub.singleRequest.DebugId, singleResp)
-
-			if singleResp.IsError() {
-				singleResp.err = errwrap.Wrapf(
-					fmt.Sprintf("Batch request and retried single request %q both failed. Final error: {{err}}", sub.singleRequest.DebugId),
-					singleResp.err)
-			}
-			sub.respCh <- singleResp
-			close(sub.respCh)
-		}
+		log.Printf("[DEBUG] Bisecting batch to isolate failing requests")
+		mid := len(batch.subscribers) / 2
+		b.sendSubscribersBisecting(batchKey, batch.BatchRequest, batch.subscribers[:mid])
+		b.sendSubscribersBisecting(batchKey, batch.BatchRequest, batch.subscribers[mid:])
 	} else {
 		// Send result to all subscribers
 		for _, sub := range batch.subscribers {
@@ -286,6 +304,77 @@ func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *starte
 	}
 }
 
+// sendSubscribersBisecting sends the combined requests of subs. If they fail,
+// each half is sent separately until the failing requests are isolated, so k
+// failing requests out of n take O(k log n) requests to find.
+func (b *RequestBatcher) sendSubscribersBisecting(batchKey string, batch *BatchRequest, subs []batchSubscriber) {
+	if len(subs) == 1 {
+		sub := subs[0]
+		log.Printf("[DEBUG] Retrying single request %q", sub.singleRequest.DebugId)
+		singleResp := sub.singleRequest.send(batchTraceContext(batchKey, subs))
+		log.Printf("[DEBUG] Retried single request %q returned response: %v", sub.singleRequest.DebugId, singleResp)
+
+		if singleResp.IsError() {
+			singleResp.err = errwrap.Wrapf(
+				fmt.Sprintf("Batch request and retried single request %q both failed. Final error: {{err}}", sub.singleRequest.DebugId),
+				singleResp.err)
+		}
+		sub.respCh <- singleResp
+		close(sub.respCh)
+		return
+	}
+
+	log.Printf("[DEBUG] Retrying %d requests from batch %q", len(subs), batchKey)
+	resp := combineSubscribers(batch, subs).send(batchTraceContext(batchKey, subs))
+	if !resp.IsError() {
+		for _, sub := range subs {
+			sub.respCh <- resp
+			close(sub.respCh)
+		}
+		return
+	}
+
+	log.Printf("[DEBUG] Retried %d requests from batch %q failed with error: %v", len(subs), batchKey, resp.err)
+	mid := len(subs) / 2
+	b.sendSubscribersBisecting(batchKey, batch, subs[:mid])
+	b.sendSubscribersBisecting(batchKey, batch, subs[mid:])
+}
+
+// combineSubscribers returns a request combining the single requests of subs,
+// sent like batch.
+func combineSubscribers(batch *BatchRequest, subs []batchSubscriber) *BatchRequest {
+	combined := &BatchRequest{
+		ResourceName: batch.ResourceName,
+		Body:         subs[0].singleRequest.Body,
+		CombineF:     batch.CombineF,
+		SendF:        batch.SendF,
+		DebugId:      fmt.Sprintf("%s (%d requests)", batch.DebugId, len(subs)),
+	}
+	for _, sub := range subs[1:] {
+		body, err := combined.CombineF(combined.Body, sub.singleRequest.Body)
+		if err != nil {
+			// Requests were combined once already, so this shouldn't happen.
+			// Fail the combined request so it is bisected further.
+			combined.SendF = func(context.Context, string, interface{}) (interface{}, error) {
+				return nil, fmt.Errorf("Provider Error: Unable to recombine request %q data: %v", sub.singleRequest.DebugId, err)
+			}
+			return combined
+		}
+		combined.Body = body
+	}
+	return combined
+}
+
+// batchTraceContext returns the context a batch combining the requests of subs
+// is sent with.
+func batchTraceContext(batchKey string, subs []batchSubscriber) context.Context {
+	debugIds := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		debugIds = append(debugIds, sub.singleRequest.DebugId)
+	}
+	return contextWithTraceInfo(context.Background(), batchKey, strings.Join(debugIds, "; "))
+}
+
 // popBatch safely gets and removes a batch with given batchkey from the
 // RequestBatcher's started batches.
 func (b *RequestBatcher) popBatch(batchKey string) *startedBatch {
@@ -324,12 +413,12 @@ func (batch *startedBatch) addRequest(newRequest *BatchRequest) (<-chan batchRes
 	return respCh, nil
 }
 
-func (req *BatchRequest) send() batchResponse {
+func (req *BatchRequest) send(ctx context.Context) batchResponse {
 	if req.SendF == nil {
 		return batchResponse{
 			err: fmt.Errorf("provider error: Batch request has no SendBatch function"),
 		}
 	}
-	v, err := req.SendF(req.ResourceName, req.Body)
+	v, err := req.SendF(ctx, req.ResourceName, req.Body)
 	return batchResponse{v, err}
 }
@@ -32,7 +32,7 @@ func TestRequestBatcher_disableBatching(t *testing.T) {
 		return currV.(int) + toAddV.(int), nil
 	}
 
-	testSendBatch := func(name string, body interface{}) (interface{}, error) {
+	testSendBatch := func(_ context.Context, name string, body interface{}) (interface{}, error) {
 		return fmt.Sprintf("%s: %d", name, body), nil
 	}
 
@@ -82,7 +82,7 @@ func TestRequestBatcher_errInCombine(t *testing.T) {
 	}
 
 	// sendBatchF is no-op
-	testSendBatch := func(_ string, _ interface{}) (interface{}, error) {
+	testSendBatch := func(_ context.Context, _ string, _ interface{}) (interface{}, error) {
 		return nil, nil
 	}
 
@@ -149,7 +149,7 @@ func TestRequestBatcher_errInSend(t *testing.T) {
 	testResource := "RESOURCE-SEND-ERROR"
 	expectedErrMsg := fmt.Sprintf("Error - batch %q contains idx %d", testResource, failIdx)
 
-	testSendBatch := func(resourceName string, body interface{}) (interface{}, error) {
+	testSendBatch := func(_ context.Context, resourceName string, body interface{}) (interface{}, error) {
 		log.Printf("[DEBUG] sendBatch body: %+v", body)
 		for _, v := range body.([]int) {
 			if v == failIdx {
@@ -216,7 +216,7 @@ func TestRequestBatcher_errTimeout(t *testing.T) {
 		return v, nil
 	}
 	// no-op
-	testSendBatch := func(resourceName string, cnt interface{}) (interface{}, error) {
+	testSendBatch := func(_ context.Context, resourceName string, cnt interface{}) (interface{}, error) {
 		return nil, nil
 	}
 
@@ -258,7 +258,7 @@ func testBasicCountBatches(t *testing.T, testName string, numBatches int) {
 		return currV.(int) + toAddV.(int), nil
 	}
 
-	testSendBatch := func(name string, body interface{}) (interface{}, error) {
+	testSendBatch := func(_ context.Context, name string, body interface{}) (interface{}, error) {
 		return fmt.Sprintf("%s: %d", name, body), nil
 	}
 
@@ -292,3 +292,176 @@ func testBasicCountBatches(t *testing.T, testName string, numBatches int) {
 		}(i)
 	}
 }
+
+func TestRequestBatcher_maxBatchSize(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&batchingConfig{
+			sendAfter:      time.Duration(5) * time.Second,
+			enableBatching: true,
+			maxBatchSize:   2,
+		})
+
+	testCombine := func(currV interface{}, toAddV interface{}) (interface{}, error) {
+		return currV.(int) + toAddV.(int), nil
+	}
+
+	testSendBatch := func(_ context.Context, name string, body interface{}) (interface{}, error) {
+		return body, nil
+	}
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	for i := 0; i < 2; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			req := &BatchRequest{
+				DebugId:      fmt.Sprintf("maxBatchSize %d", idx),
+				ResourceName: "testMaxBatchSize",
+				Body:         1,
+				CombineF:     testCombine,
+				SendF:        testSendBatch,
+			}
+
+			respV, err := testBatcher.SendRequestWithTimeout("batchMaxSize", req, time.Duration(10)*time.Second)
+			if err != nil {
+				t.Errorf("got unexpected error %s", err)
+			}
+			if respV != 2 {
+				t.Errorf("expected both requests to be combined, got response %v", respV)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// The batch was full, so should have been sent before sendAfter.
+	if elapsed := time.Since(start); elapsed >= time.Duration(5)*time.Second {
+		t.Errorf("expected full batch to be sent early, took %s", elapsed)
+	}
+}
+
+func TestRequestBatcher_maxBatchSizeOne(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&batchingConfig{
+			sendAfter:      time.Duration(5) * time.Second,
+			enableBatching: true,
+			maxBatchSize:   1,
+		})
+
+	testCombine := func(currV interface{}, toAddV interface{}) (interface{}, error) {
+		return currV.(int) + toAddV.(int), nil
+	}
+
+	testSendBatch := func(_ context.Context, name string, body interface{}) (interface{}, error) {
+		return body, nil
+	}
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	for i := 0; i < 2; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			req := &BatchRequest{
+				DebugId:      fmt.Sprintf("maxBatchSizeOne %d", idx),
+				ResourceName: "testMaxBatchSizeOne",
+				Body:         1,
+				CombineF:     testCombine,
+				SendF:        testSendBatch,
+			}
+
+			respV, err := testBatcher.SendRequestWithTimeout("batchMaxSizeOne", req, time.Duration(10)*time.Second)
+			if err != nil {
+				t.Errorf("got unexpected error %s", err)
+			}
+			if respV != 1 {
+				t.Errorf("expected requests to be sent alone, got response %v", respV)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// Each batch was full when created, so should have been sent before sendAfter.
+	if elapsed := time.Since(start); elapsed >= time.Duration(5)*time.Second {
+		t.Errorf("expected full batches to be sent early, took %s", elapsed)
+	}
+}
+
+func TestRequestBatcher_bisectsFailedBatch(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&batchingConfig{
+			sendAfter:      time.Duration(1) * time.Second,
+			enableBatching: true,
+		})
+
+	// combineF keeps track of the batched indexes
+	testCombine := func(body interface{}, toAdd interface{}) (interface{}, error) {
+		return append(append([]int{}, body.([]int)...), toAdd.([]int)...), nil
+	}
+
+	failIdx := 5
+	expectedErrMsg := fmt.Sprintf("Error - batch contains idx %d", failIdx)
+
+	var sendsMutex sync.Mutex
+	sends := 0
+	testSendBatch := func(_ context.Context, resourceName string, body interface{}) (interface{}, error) {
+		sendsMutex.Lock()
+		sends++
+		sendsMutex.Unlock()
+		for _, v := range body.([]int) {
+			if v == failIdx {
+				return nil, fmt.Errorf(expectedErrMsg)
+			}
+		}
+		return nil, nil
+	}
+
+	numRequests := 16
+
+	wg := sync.WaitGroup{}
+	wg.Add(numRequests)
+
+	for i := 0; i < numRequests; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			req := &BatchRequest{
+				DebugId:      fmt.Sprintf("bisect %d", idx),
+				ResourceName: "testBisect",
+				Body:         []int{idx},
+				CombineF:     testCombine,
+				SendF:        testSendBatch,
+			}
+
+			_, err := testBatcher.SendRequestWithTimeout("batchBisect", req, time.Duration(10)*time.Second)
+			if idx == failIdx {
+				if err == nil {
+					t.Errorf("expected error for request %d, got none", idx)
+				} else if !strings.Contains(err.Error(), expectedErrMsg) {
+					t.Errorf("expected error %q to contain %q", err, expectedErrMsg)
+				}
+			} else if err != nil {
+				t.Errorf("expected request %d to succeed, got error: %v", idx, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// One combined send, then two sends per level of bisection.
+	if expected := 1 + 2*4; sends != expected {
+		t.Errorf("expected %d sends to isolate a single failing request out of %d, got %d", expected, numRequests, sends)
+	}
+}
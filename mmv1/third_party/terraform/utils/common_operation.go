@@ -1,11 +1,13 @@
 package google
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 )
 
@@ -51,6 +53,14 @@ type Waiter interface {
 	TargetStates() []string
 }
 
+// operationProgressReporter is implemented by Waiters that can describe the
+// progress of their operation, which is logged while polling it.
+type operationProgressReporter interface {
+	// Progress describes the progress of the operation, or is empty if it's
+	// unknown.
+	Progress() string
+}
+
 type CommonOperationWaiter struct {
 	Op CommonOperation
 }
@@ -89,6 +99,15 @@ func (w *CommonOperationWaiter) OpName() string {
 	return w.Op.Name
 }
 
+// Progress returns the metadata of the operation, where APIs report its
+// progress.
+func (w *CommonOperationWaiter) Progress() string {
+	if w == nil || len(w.Op.Metadata) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("metadata: %s", w.Op.Metadata)
+}
+
 func (w *CommonOperationWaiter) PendingStates() []string {
 	return []string{"done: false"}
 }
@@ -113,7 +132,7 @@ func CommonRefreshFunc(w Waiter) resource.StateRefreshFunc {
 			// Retry 404 when getting operation (not resource state)
 			if isRetryableError(err, isNotFoundRetryableError("GET operation"), isOperationReadQuotaError) {
 				log.Printf("[DEBUG] Dismissed retryable error on GET operation %q: %s", w.OpName(), err)
-				return nil, "done: false", nil
+				return nil, w.PendingStates()[0], nil
 			}
 			return nil, "", fmt.Errorf("error while retrieving operation: %s", err)
 		}
@@ -131,15 +150,26 @@ func CommonRefreshFunc(w Waiter) resource.StateRefreshFunc {
 		}
 
 		log.Printf("[DEBUG] Got %v while polling for operation %s's status", w.State(), w.OpName())
+		if r, ok := w.(operationProgressReporter); ok {
+			if progress := r.Progress(); progress != "" {
+				log.Printf("[DEBUG] Progress of operation %s: %s", w.OpName(), progress)
+			}
+		}
 		return op, w.State(), nil
 	}
 }
 
 func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	if err := operationWait(w, timeout, pollInterval); err != nil {
+		return fmt.Errorf("Error waiting for %s: %s", activity, err)
+	}
+	return w.Error()
+}
+
+// operationWait waits for the operation of w to finish, and returns any error
+// from waiting for it, unwrapped.
+func operationWait(w Waiter, timeout time.Duration, pollInterval time.Duration) error {
 	if OperationDone(w) {
-		if w.Error() != nil {
-			return w.Error()
-		}
 		return nil
 	}
 
@@ -153,18 +183,98 @@ func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterva
 	}
 	opRaw, err := c.WaitForState()
 	if err != nil {
-		return fmt.Errorf("Error waiting for %s: %s", activity, err)
+		return err
+	}
+
+	return w.SetOp(opRaw)
+}
+
+// The schema key of the computed field resources that support resuming
+// operations save the name of a timed out operation under.
+//
+// Resuming is opt-in per resource, and only composer environments opt in. A
+// resource adds pendingOperationSchema under this key, uses
+// resumePendingOperationDiff as its CustomizeDiff, waits with
+// OperationWaitResumable, and calls ResumePendingOperation in Update and
+// Delete. Its waiter must be able to query an operation from the name alone,
+// so compute and deployment manager operations, which are queried by their
+// zone, region or self link, can't be resumed.
+const pendingOperationKey = "pending_operation"
+
+// pendingOperationSchema returns the schema of the pending_operation field.
+func pendingOperationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: `The name of an operation on this resource that timed out and is still being waited for.`,
+	}
+}
+
+// resumePendingOperationDiff plans an update of resources that have a
+// pending_operation, so that the next apply resumes waiting for it in Update
+// rather than in Read, which would block planning.
+func resumePendingOperationDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if name, ok := diff.Get(pendingOperationKey).(string); ok && name != "" {
+		return diff.SetNew(pendingOperationKey, "")
 	}
+	return nil
+}
+
+// PendingOperationError is returned by OperationWaitResumable when waiting for
+// an operation timed out, and the operation was saved to be resumed.
+type PendingOperationError struct {
+	Name     string
+	Activity string
+	Err      error
+}
 
-	err = w.SetOp(opRaw)
+func (e *PendingOperationError) Error() string {
+	return fmt.Sprintf("Error waiting for %s: %s. The operation %q is still running, and will be waited for again on the next apply.", e.Activity, e.Err, e.Name)
+}
+
+// OperationWaitResumable is like OperationWait, but if waiting times out the
+// operation name is saved to pending_operation in d, and a
+// *PendingOperationError is returned. ResumePendingOperation resumes waiting
+// for the operation later.
+func OperationWaitResumable(d TerraformResourceData, w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	err := operationWait(w, timeout, pollInterval)
+	if _, ok := err.(*resource.TimeoutError); ok {
+		if serr := d.Set(pendingOperationKey, w.OpName()); serr != nil {
+			return fmt.Errorf("Error waiting for %s: %s. Unable to save operation %q: %s", activity, err, w.OpName(), serr)
+		}
+		return &PendingOperationError{Name: w.OpName(), Activity: activity, Err: err}
+	}
 	if err != nil {
-		return err
+		return fmt.Errorf("Error waiting for %s: %s", activity, err)
 	}
-	if w.Error() != nil {
-		return w.Error()
+	return w.Error()
+}
+
+// ResumePendingOperation waits for the operation saved by
+// OperationWaitResumable to pending_operation in d, if any. The operation is
+// cleared once it's done, and its error returned. If waiting times out again,
+// a *PendingOperationError is returned and the operation is kept.
+func ResumePendingOperation(d *schema.ResourceData, w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	// The operation is read from the prior state, as resumePendingOperationDiff
+	// plans it to be cleared.
+	old, _ := d.GetChange(pendingOperationKey)
+	name, ok := old.(string)
+	if !ok || name == "" {
+		return nil
 	}
 
-	return nil
+	log.Printf("[DEBUG] Resuming wait for operation %q", name)
+	if err := w.SetOp(map[string]interface{}{"name": name}); err != nil {
+		return err
+	}
+	err := OperationWaitResumable(d, w, activity, timeout, pollInterval)
+	if _, ok := err.(*PendingOperationError); ok {
+		return err
+	}
+	if serr := d.Set(pendingOperationKey, ""); serr != nil {
+		return fmt.Errorf("Error clearing operation %q: %s", name, serr)
+	}
+	return err
 }
 
 // The cloud resource manager API operation is an example of one of many
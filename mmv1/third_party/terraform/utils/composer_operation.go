@@ -1,30 +1,35 @@
 package google
 
 import (
-	"fmt"
 	"time"
 
 	composer "google.golang.org/api/composer/v1beta1"
 )
 
-type ComposerOperationWaiter struct {
-	Service *composer.ProjectsLocationsService
-	CommonOperationWaiter
+func newComposerOperationWaiter(config *Config, userAgent string) *ShapedOperationWaiter {
+	service := config.NewComposerClient(userAgent).Projects.Locations
+	return &ShapedOperationWaiter{
+		Shape: LongRunningOperationShape,
+		QueryF: func(name string) (interface{}, error) {
+			return service.Operations.Get(name).Do()
+		},
+	}
 }
 
-func (w *ComposerOperationWaiter) QueryOp() (interface{}, error) {
-	if w == nil {
-		return nil, fmt.Errorf("Cannot query operation, it's unset or nil.")
+func composerOperationWaitTime(config *Config, op *composer.Operation, activity, userAgent string, timeout time.Duration) error {
+	w := newComposerOperationWaiter(config, userAgent)
+	if err := w.SetOp(op); err != nil {
+		return err
 	}
-	return w.Service.Operations.Get(w.Op.Name).Do()
+	return OperationWait(w, activity, timeout, config.PollInterval)
 }
 
-func composerOperationWaitTime(config *Config, op *composer.Operation, project, activity, userAgent string, timeout time.Duration) error {
-	w := &ComposerOperationWaiter{
-		Service: config.NewComposerClient(userAgent).Projects.Locations,
-	}
+// composerOperationWaitTimeResumable is like composerOperationWaitTime, but
+// saves the operation to d if waiting times out. See OperationWaitResumable.
+func composerOperationWaitTimeResumable(d TerraformResourceData, config *Config, op *composer.Operation, activity, userAgent string, timeout time.Duration) error {
+	w := newComposerOperationWaiter(config, userAgent)
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	return OperationWait(w, activity, timeout, config.PollInterval)
+	return OperationWaitResumable(d, w, activity, timeout, config.PollInterval)
 }
@@ -307,3 +307,103 @@ func TestRemoveBasePathVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandProviderRetryConfig(t *testing.T) {
+	retryCfg, err := expandProviderRetryConfig(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retryCfg.timeout != time.Second*defaultRetryTransportTimeoutSec {
+		t.Fatalf("expected default timeout to be %d seconds, got %v", defaultRetryTransportTimeoutSec, retryCfg.timeout)
+	}
+
+	retryCfg, err = expandProviderRetryConfig([]interface{}{
+		map[string]interface{}{
+			"timeout":         "5m",
+			"initial_backoff": "1s",
+			"max_backoff":     "1m",
+			"max_attempts":    10,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retryCfg.timeout != 5*time.Minute {
+		t.Fatalf("expected timeout to be 5 minutes, got %v", retryCfg.timeout)
+	}
+	if retryCfg.initialBackoff != time.Second {
+		t.Fatalf("expected initialBackoff to be 1 second, got %v", retryCfg.initialBackoff)
+	}
+	if retryCfg.maxBackoff != time.Minute {
+		t.Fatalf("expected maxBackoff to be 1 minute, got %v", retryCfg.maxBackoff)
+	}
+	if retryCfg.maxAttempts != 10 {
+		t.Fatalf("expected maxAttempts to be 10, got %d", retryCfg.maxAttempts)
+	}
+
+	_, err = expandProviderRetryConfig([]interface{}{
+		map[string]interface{}{
+			"initial_backoff": "1m",
+			"max_backoff":     "1s",
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected error for initial_backoff greater than max_backoff")
+	}
+}
+
+func TestExpandProviderRateLimits(t *testing.T) {
+	limits, err := expandProviderRateLimits([]interface{}{
+		map[string]interface{}{
+			"host":                "iam.googleapis.com",
+			"method":              "",
+			"requests_per_second": 2.5,
+			"burst":               3,
+		},
+		map[string]interface{}{
+			"host":                "iam.googleapis.com",
+			"method":              "setIamPolicy",
+			"requests_per_second": 1.0,
+			"burst":               1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(limits) != 2 {
+		t.Fatalf("expected 2 rate limits, got %d", len(limits))
+	}
+	if limits[0].requestsPerSecond != 2.5 || limits[0].burst != 3 {
+		t.Fatalf("expected 2.5 requests per second with a burst of 3, got %v with a burst of %d", limits[0].requestsPerSecond, limits[0].burst)
+	}
+	if limits[1].method != "setIamPolicy" {
+		t.Fatalf("expected method to be setIamPolicy, got %q", limits[1].method)
+	}
+
+	_, err = expandProviderRateLimits([]interface{}{
+		map[string]interface{}{
+			"host":                "iam.googleapis.com",
+			"requests_per_second": 0.0,
+			"burst":               1,
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected error for a non-positive rate")
+	}
+
+	_, err = expandProviderRateLimits([]interface{}{
+		map[string]interface{}{
+			"host":                "iam.googleapis.com",
+			"requests_per_second": 1.0,
+			"burst":               1,
+		},
+		map[string]interface{}{
+			"host":                "iam.googleapis.com",
+			"requests_per_second": 2.0,
+			"burst":               1,
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected error for a duplicate rate limit")
+	}
+}
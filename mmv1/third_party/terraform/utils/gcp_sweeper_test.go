@@ -1,10 +1,19 @@
 package google
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
@@ -15,6 +24,29 @@ var testResourcePrefixes = []string{
 	"gke-us-central1-tf", // composer-created disks which are abandoned by design (https://cloud.google.com/composer/pricing)
 }
 
+// Environment variables configuring sweepers. Sweepers run once for each
+// region passed to -sweep, such as -sweep=us-central1,us-east1.
+const (
+	// Set to "true" to list the resources that would be swept without
+	// deleting them.
+	sweeperDryRunEnvVar = "SWEEPER_DRY_RUN"
+	// A duration such as "3h". Resources created more recently, or whose
+	// creation time is unknown, are skipped.
+	sweeperMinAgeEnvVar = "SWEEPER_MIN_AGE"
+	// The projects to sweep, separated by commas. Defaults to the test project.
+	sweeperProjectsEnvVar = "SWEEPER_PROJECTS"
+	// The file a JSON report of swept, skipped and failed resources is
+	// written to.
+	sweeperReportFileEnvVar = "SWEEPER_REPORT_FILE"
+)
+
+// The fields APIs report the creation time of resources in.
+var sweeperCreateTimeFields = []string{
+	"createTime",
+	"creationTimestamp",
+	"timeCreated",
+}
+
 func TestMain(m *testing.M) {
 	resource.TestMain(m)
 }
@@ -22,7 +54,12 @@ func TestMain(m *testing.M) {
 // sharedConfigForRegion returns a common config setup needed for the sweeper
 // functions for a given region
 func sharedConfigForRegion(region string) (*Config, error) {
-	project := getTestProjectFromEnv()
+	return sharedConfigForProjectAndRegion(getTestProjectFromEnv(), region)
+}
+
+// sharedConfigForProjectAndRegion returns a common config setup needed for
+// the sweeper functions for a given project and region
+func sharedConfigForProjectAndRegion(project, region string) (*Config, error) {
 	if project == "" {
 		return nil, fmt.Errorf("set project using any of these env variables %v", projectEnvVars)
 	}
@@ -51,3 +88,356 @@ func isSweepableTestResource(resourceName string) bool {
 	}
 	return false
 }
+
+// sweeperOptions configure all sweepers, and are read from the environment.
+type sweeperOptions struct {
+	dryRun     bool
+	minAge     time.Duration
+	projects   []string
+	reportFile string
+}
+
+func sweeperOptionsFromEnv() (*sweeperOptions, error) {
+	opts := &sweeperOptions{
+		dryRun:     os.Getenv(sweeperDryRunEnvVar) == "true",
+		reportFile: os.Getenv(sweeperReportFileEnvVar),
+	}
+
+	if v := os.Getenv(sweeperMinAgeEnvVar); v != "" {
+		minAge, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %s", sweeperMinAgeEnvVar, v, err)
+		}
+		opts.minAge = minAge
+	}
+
+	for _, p := range strings.Split(os.Getenv(sweeperProjectsEnvVar), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			opts.projects = append(opts.projects, p)
+		}
+	}
+	if len(opts.projects) == 0 {
+		opts.projects = []string{getTestProjectFromEnv()}
+	}
+
+	return opts, nil
+}
+
+// sweeperSkippedResource is a resource a sweeper didn't delete.
+type sweeperSkippedResource struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// sweeperFailedResource is a resource a sweeper failed to delete. Name is
+// empty if the sweeper failed before it got to individual resources, such as
+// when listing them.
+type sweeperFailedResource struct {
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// sweeperRun records what a sweeper did in a single project and region.
+type sweeperRun struct {
+	Sweeper string `json:"sweeper"`
+	Project string `json:"project"`
+	Region  string `json:"region"`
+	DryRun  bool   `json:"dry_run"`
+	// Swept are the resources deleted, or that would have been in a dry run.
+	Swept   []string                 `json:"swept"`
+	Skipped []sweeperSkippedResource `json:"skipped"`
+	Failed  []sweeperFailedResource  `json:"failed"`
+
+	minAge time.Duration
+	now    time.Time
+	mutex  sync.Mutex
+}
+
+func newSweeperRun(sweeper, project, region string, opts *sweeperOptions) *sweeperRun {
+	return &sweeperRun{
+		Sweeper: sweeper,
+		Project: project,
+		Region:  region,
+		DryRun:  opts.dryRun,
+		minAge:  opts.minAge,
+		now:     time.Now(),
+	}
+}
+
+// shouldSweep reports whether the resource named name, created at createTime
+// in RFC 3339 format, should be deleted. Resources that shouldn't are
+// recorded as skipped, and in a dry run the resources that would be deleted
+// are recorded as swept instead.
+func (r *sweeperRun) shouldSweep(name, createTime string) bool {
+	if !isSweepableTestResource(name) {
+		r.skip(name, "not a test resource")
+		return false
+	}
+	return r.shouldSweepUnprefixed(name, createTime)
+}
+
+// shouldSweepUnprefixed is shouldSweep for resources whose names don't carry
+// a test prefix, such as those in a parent only used by tests.
+func (r *sweeperRun) shouldSweepUnprefixed(name, createTime string) bool {
+	if r.minAge > 0 {
+		if createTime == "" {
+			r.skip(name, "unknown creation time")
+			return false
+		}
+		created, err := time.Parse(time.RFC3339, createTime)
+		if err != nil {
+			r.skip(name, fmt.Sprintf("invalid creation time %q", createTime))
+			return false
+		}
+		if age := r.now.Sub(created); age < r.minAge {
+			r.skip(name, fmt.Sprintf("created %s ago", age.Round(time.Second)))
+			return false
+		}
+	}
+
+	if r.DryRun {
+		log.Printf("[INFO][SWEEPER_LOG] Dry run, would delete %s resource: %s", r.Sweeper, name)
+		r.mutex.Lock()
+		r.Swept = append(r.Swept, name)
+		r.mutex.Unlock()
+		return false
+	}
+	return true
+}
+
+// deleted records the result of deleting the resource named name.
+func (r *sweeperRun) deleted(name string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err != nil {
+		log.Printf("[INFO][SWEEPER_LOG] Error deleting %s resource %s: %s", r.Sweeper, name, err)
+		r.Failed = append(r.Failed, sweeperFailedResource{Name: name, Error: err.Error()})
+		return
+	}
+	log.Printf("[INFO][SWEEPER_LOG] Sent delete request for %s resource: %s", r.Sweeper, name)
+	r.Swept = append(r.Swept, name)
+}
+
+// deletedAll records the result of deleting the resources named names
+// together, such as with the DCL's DeleteAll. The DCL doesn't report which
+// deletions failed, so on error all of them are recorded as failed. The
+// sweeper should still return err, which runSweeper records as a failure of
+// the sweeper itself.
+func (r *sweeperRun) deletedAll(names []string, err error) {
+	if err != nil {
+		err = fmt.Errorf("deleting one or more of %d resources failed: %s", len(names), err)
+	}
+	for _, name := range names {
+		r.deleted(name, err)
+	}
+}
+
+// fail records a failure of the sweeper not specific to a single resource.
+func (r *sweeperRun) fail(err error) {
+	log.Printf("[INFO][SWEEPER_LOG] %s", err)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Failed = append(r.Failed, sweeperFailedResource{Error: err.Error()})
+}
+
+func (r *sweeperRun) skip(name, reason string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Skipped = append(r.Skipped, sweeperSkippedResource{Name: name, Reason: reason})
+}
+
+func (r *sweeperRun) logSummary() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	swept := "swept"
+	if r.DryRun {
+		swept = "would be swept"
+	}
+	log.Printf("[INFO][SWEEPER_LOG] %s in project %q, region %q: %d %s, %d skipped, %d failed.", r.Sweeper, r.Project, r.Region, len(r.Swept), swept, len(r.Skipped), len(r.Failed))
+}
+
+// sweeperReport collects the runs of all sweepers, which are written to the
+// report file after every run since the test binary exits once sweepers are
+// done.
+type sweeperReport struct {
+	mutex sync.Mutex
+	Runs  []*sweeperRun `json:"runs"`
+}
+
+var sweeperResults = &sweeperReport{}
+
+func (rep *sweeperReport) add(run *sweeperRun, reportFile string) error {
+	rep.mutex.Lock()
+	defer rep.mutex.Unlock()
+	rep.Runs = append(rep.Runs, run)
+	if reportFile == "" {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(reportFile, b, 0644)
+}
+
+// runSweeper calls sweep for each project in SWEEPER_PROJECTS with a loaded
+// config for the project and region, and adds what it did to the sweeper
+// report. sweep should only delete resources that run.shouldSweep allows, and
+// record the results with run.deleted or run.deletedAll.
+func runSweeper(name, region string, sweep func(config *Config, run *sweeperRun) error) error {
+	log.Printf("[INFO][SWEEPER_LOG] Starting sweeper for %s", name)
+
+	opts, err := sweeperOptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	return runSweeperForProjects(name, region, opts.projects, opts, sweep)
+}
+
+// runOrgSweeper is runSweeper for sweepers of resources outside of projects,
+// which only run with the test project.
+func runOrgSweeper(name, region string, sweep func(config *Config, run *sweeperRun) error) error {
+	log.Printf("[INFO][SWEEPER_LOG] Starting sweeper for %s", name)
+
+	opts, err := sweeperOptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	return runSweeperForProjects(name, region, []string{getTestProjectFromEnv()}, opts, sweep)
+}
+
+func runSweeperForProjects(name, region string, projects []string, opts *sweeperOptions, sweep func(config *Config, run *sweeperRun) error) error {
+	var errs *multierror.Error
+	for _, project := range projects {
+		run := newSweeperRun(name, project, region, opts)
+		if err := runSweeperInProject(region, project, run, sweep); err != nil {
+			run.fail(err)
+			errs = multierror.Append(errs, err)
+		}
+		run.logSummary()
+		if err := sweeperResults.add(run, opts.reportFile); err != nil {
+			log.Printf("[INFO][SWEEPER_LOG] error writing sweeper report: %s", err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func runSweeperInProject(region, project string, run *sweeperRun, sweep func(config *Config, run *sweeperRun) error) error {
+	config, err := sharedConfigForProjectAndRegion(project, region)
+	if err != nil {
+		return fmt.Errorf("error getting shared config for project %q and region %q: %s", project, region, err)
+	}
+
+	err = config.LoadAndValidate(context.Background())
+	if err != nil {
+		return fmt.Errorf("error loading: %s", err)
+	}
+
+	return sweep(config, run)
+}
+
+// sweepableResourceCreateTime returns the creation time of a resource in the
+// list response of an API, or "" if it has none.
+func sweepableResourceCreateTime(obj map[string]interface{}) string {
+	for _, f := range sweeperCreateTimeFields {
+		if v, ok := obj[f].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func TestSweeperRun_shouldSweep(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	run := &sweeperRun{Sweeper: "ComputeDisk", minAge: 3 * time.Hour, now: now}
+
+	cases := []struct {
+		name       string
+		createTime string
+		expected   bool
+	}{
+		{"tf-test-old", "2021-06-01T01:00:00.000-07:00", true},
+		{"tf-test-new", "2021-06-01T11:00:00Z", false},
+		{"tf-test-unknown", "", false},
+		{"tf-test-invalid", "yesterday", false},
+		{"production", "2020-01-01T00:00:00Z", false},
+	}
+	for _, tc := range cases {
+		if got := run.shouldSweep(tc.name, tc.createTime); got != tc.expected {
+			t.Errorf("expected shouldSweep(%q, %q) to be %t, got %t", tc.name, tc.createTime, tc.expected, got)
+		}
+	}
+
+	expectedSkipped := []sweeperSkippedResource{
+		{Name: "tf-test-new", Reason: "created 1h0m0s ago"},
+		{Name: "tf-test-unknown", Reason: "unknown creation time"},
+		{Name: "tf-test-invalid", Reason: `invalid creation time "yesterday"`},
+		{Name: "production", Reason: "not a test resource"},
+	}
+	if len(run.Skipped) != len(expectedSkipped) {
+		t.Fatalf("expected skipped resources %v, got %v", expectedSkipped, run.Skipped)
+	}
+	for i, s := range expectedSkipped {
+		if run.Skipped[i] != s {
+			t.Errorf("expected skipped resource %v, got %v", s, run.Skipped[i])
+		}
+	}
+}
+
+func TestSweeperRun_dryRun(t *testing.T) {
+	run := &sweeperRun{Sweeper: "ComputeDisk", DryRun: true, now: time.Now()}
+
+	if run.shouldSweep("tf-test-disk", "") {
+		t.Errorf("expected resources not to be deleted in a dry run")
+	}
+	if len(run.Swept) != 1 || run.Swept[0] != "tf-test-disk" {
+		t.Errorf("expected dry run to record tf-test-disk as swept, got %v", run.Swept)
+	}
+}
+
+func TestSweeperRun_deletedAll(t *testing.T) {
+	run := &sweeperRun{Sweeper: "ComputeDisk", now: time.Now()}
+	run.deletedAll([]string{"tf-test-a", "tf-test-b"}, nil)
+	if !reflect.DeepEqual(run.Swept, []string{"tf-test-a", "tf-test-b"}) || len(run.Failed) != 0 {
+		t.Errorf("expected both resources to be swept, got swept %v and failed %v", run.Swept, run.Failed)
+	}
+
+	run = &sweeperRun{Sweeper: "ComputeDisk", now: time.Now()}
+	run.deletedAll([]string{"tf-test-a", "tf-test-b"}, fmt.Errorf("quota exceeded"))
+	if len(run.Swept) != 0 || len(run.Failed) != 2 || run.Failed[0].Name != "tf-test-a" || run.Failed[1].Name != "tf-test-b" {
+		t.Errorf("expected both resources to fail, got swept %v and failed %v", run.Swept, run.Failed)
+	}
+
+	run = &sweeperRun{Sweeper: "ComputeDisk", now: time.Now()}
+	run.deletedAll(nil, fmt.Errorf("listing failed"))
+	if len(run.Swept) != 0 || len(run.Failed) != 0 {
+		t.Errorf("expected the error to be left to the sweeper, got swept %v and failed %v", run.Swept, run.Failed)
+	}
+}
+
+func TestSweeperOptionsFromEnv(t *testing.T) {
+	for _, k := range []string{sweeperDryRunEnvVar, sweeperMinAgeEnvVar, sweeperProjectsEnvVar, sweeperReportFileEnvVar} {
+		defer os.Setenv(k, os.Getenv(k))
+	}
+	os.Setenv(sweeperDryRunEnvVar, "true")
+	os.Setenv(sweeperMinAgeEnvVar, "90m")
+	os.Setenv(sweeperProjectsEnvVar, "project-a, project-b,")
+	os.Setenv(sweeperReportFileEnvVar, "")
+
+	opts, err := sweeperOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !opts.dryRun || opts.minAge != 90*time.Minute {
+		t.Errorf("expected a dry run with a minimum age of 90m, got %+v", opts)
+	}
+	if len(opts.projects) != 2 || opts.projects[0] != "project-a" || opts.projects[1] != "project-b" {
+		t.Errorf("expected projects [project-a project-b], got %v", opts.projects)
+	}
+
+	os.Setenv(sweeperMinAgeEnvVar, "a while")
+	if _, err := sweeperOptionsFromEnv(); err == nil {
+		t.Errorf("expected error for an invalid minimum age")
+	}
+}
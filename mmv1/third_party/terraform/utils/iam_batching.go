@@ -1,6 +1,7 @@
 package google
 
 import (
+	"context"
 	"fmt"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	"time"
@@ -43,7 +44,9 @@ func combineBatchIamPolicyModifiers(currV interface{}, toAddV interface{}) (inte
 }
 
 func sendBatchModifyIamPolicy(updater ResourceIamUpdater) BatcherSendFunc {
-	return func(resourceName string, body interface{}) (interface{}, error) {
+	// ResourceIamUpdater methods don't take a context, so requests made to send
+	// the batch aren't traced with its batch key.
+	return func(_ context.Context, resourceName string, body interface{}) (interface{}, error) {
 		modifiers, ok := body.([]iamPolicyModifyFunc)
 		if !ok {
 			return nil, fmt.Errorf("provider error: expected data to be type []iamPolicyModifyFunc, got %v with type %T", body, body)
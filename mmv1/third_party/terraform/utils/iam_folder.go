@@ -86,6 +86,12 @@ func (u *FolderIamUpdater) DescribeResource() string {
 	return fmt.Sprintf("folder %q", u.folderId)
 }
 
+// Resource Manager rejects policies written with a stale etag, so concurrent
+// changes don't need to be prevented by locking the policy.
+func (u *FolderIamUpdater) IamPolicyOptions() *iamPolicyOptions {
+	return &iamPolicyOptions{etagConcurrency: true}
+}
+
 func canonicalFolderId(folder string) string {
 	if strings.HasPrefix(folder, "folders/") {
 		return folder
@@ -80,3 +80,9 @@ func (u *OrganizationIamUpdater) GetMutexKey() string {
 func (u *OrganizationIamUpdater) DescribeResource() string {
 	return fmt.Sprintf("organization %q", u.resourceId)
 }
+
+// Resource Manager rejects policies written with a stale etag, so concurrent
+// changes don't need to be prevented by locking the policy.
+func (u *OrganizationIamUpdater) IamPolicyOptions() *iamPolicyOptions {
+	return &iamPolicyOptions{etagConcurrency: true}
+}
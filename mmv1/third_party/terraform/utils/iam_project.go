@@ -124,6 +124,12 @@ func (u *ProjectIamUpdater) DescribeResource() string {
 	return fmt.Sprintf("project %q", u.resourceId)
 }
 
+// Resource Manager rejects policies written with a stale etag, so concurrent
+// changes don't need to be prevented by locking the policy.
+func (u *ProjectIamUpdater) IamPolicyOptions() *iamPolicyOptions {
+	return &iamPolicyOptions{etagConcurrency: true}
+}
+
 func compareProjectName(_, old, new string, _ *schema.ResourceData) bool {
 	// We can either get "projects/project-id" or "project-id", so strip any prefixes
 	return GetResourceNameFromSelfLink(old) == GetResourceNameFromSelfLink(new)
@@ -1,51 +1,330 @@
 package google
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"runtime"
+	"strings"
 	"sync"
+	"time"
 )
 
-// MutexKV is a simple key/value store for arbitrary mutexes. It can be used to
-// serialize changes across arbitrary collaborators that share knowledge of the
-// keys they must serialize on.
+// MutexKV is a simple key/value store for arbitrary read/write mutexes. It can
+// be used to serialize changes across arbitrary collaborators that share
+// knowledge of the keys they must serialize on.
 //
 // The initial use case is to let aws_security_group_rule resources serialize
 // their access to individual security groups based on SG ID.
+//
+// Each key records who holds it and since when, which is reported when a
+// caller gives up waiting for the key and by the optional watchdog.
 type MutexKV struct {
 	lock  sync.Mutex
-	store map[string]*sync.Mutex
+	store map[string]*keyMutex
+
+	watchdogOnce      sync.Once
+	watchdogThreshold time.Duration
+}
+
+// mutexHolder describes a single holder of a key.
+type mutexHolder struct {
+	debugId string
+	// named is set when debugId was set by the caller, rather than derived
+	// from the caller's location.
+	named    bool
+	write    bool
+	acquired time.Time
+}
+
+func (h mutexHolder) String() string {
+	mode := "read"
+	if h.write {
+		mode = "write"
+	}
+	return fmt.Sprintf("%s (%s, held for %s)", h.debugId, mode, time.Since(h.acquired).Round(time.Second))
+}
+
+// keyMutex is a read/write mutex whose lock methods can be cancelled. Writers
+// waiting for the key block new readers, so writers aren't starved.
+type keyMutex struct {
+	mutex          sync.Mutex
+	writer         bool
+	readers        int
+	writersWaiting int
+	// holders of the key, in order of acquisition.
+	holders []mutexHolder
+	// changed is closed and replaced whenever the key is released, to wake
+	// waiting callers.
+	changed chan struct{}
+}
+
+type mutexHolderContextKey struct{}
+
+// ContextWithMutexHolder returns a context that identifies the caller as
+// debugId when it holds keys locked with it, such as the id of the Terraform
+// resource being changed.
+func ContextWithMutexHolder(ctx context.Context, debugId string) context.Context {
+	return context.WithValue(ctx, mutexHolderContextKey{}, debugId)
+}
+
+// resourceMutexContext returns a context for locking keys on behalf of a
+// Terraform resource operation, such as "ComputeNetwork create", on the
+// resource with the given id. Waiting for a key gives up after timeout, which
+// is usually the d.Timeout of the operation.
+func resourceMutexContext(operation, id string, timeout time.Duration) (context.Context, context.CancelFunc) {
+	debugId := operation
+	if id != "" {
+		debugId = fmt.Sprintf("%s %q", operation, id)
+	}
+	return context.WithTimeout(ContextWithMutexHolder(context.Background(), debugId), timeout)
+}
+
+// newMutexHolder returns the holder set on ctx, or the location of the caller
+// outside of MutexKV if none is set.
+func newMutexHolder(ctx context.Context, write bool) mutexHolder {
+	if debugId, ok := ctx.Value(mutexHolderContextKey{}).(string); ok && debugId != "" {
+		return mutexHolder{debugId: debugId, named: true, write: write}
+	}
+	for skip := 2; ; skip++ {
+		pc, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			return mutexHolder{debugId: "unknown", write: write}
+		}
+		if fn := runtime.FuncForPC(pc); fn != nil && strings.Contains(fn.Name(), "MutexKV") {
+			continue
+		}
+		return mutexHolder{debugId: fmt.Sprintf("%s:%d", file, line), write: write}
+	}
 }
 
 // Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *MutexKV) Lock(key string) {
-	log.Printf("[DEBUG] Locking %q", key)
-	m.get(key).Lock()
-	log.Printf("[DEBUG] Locked %q", key)
+	// The background context is never done, so there is no error.
+	_ = m.LockContext(context.Background(), key)
+}
+
+// LockContext locks the mutex for the given key, or returns an error if ctx is
+// done first. Caller is responsible for calling Unlock for the same key if no
+// error is returned.
+func (m *MutexKV) LockContext(ctx context.Context, key string) error {
+	return m.lockContext(ctx, key, true)
 }
 
 // Unlock the mutex for the given key. Caller must have called Lock for the same key first
 func (m *MutexKV) Unlock(key string) {
 	log.Printf("[DEBUG] Unlocking %q", key)
-	m.get(key).Unlock()
+	m.get(key).unlock(true)
 	log.Printf("[DEBUG] Unlocked %q", key)
 }
 
+// RLock locks the mutex for the given key for reading. Any number of readers
+// may hold a key at once, but not at the same time as a writer. Caller is
+// responsible for calling RUnlock for the same key.
+func (m *MutexKV) RLock(key string) {
+	// The background context is never done, so there is no error.
+	_ = m.RLockContext(context.Background(), key)
+}
+
+// RLockContext locks the mutex for the given key for reading, or returns an
+// error if ctx is done first. Caller is responsible for calling RUnlock for
+// the same key if no error is returned.
+func (m *MutexKV) RLockContext(ctx context.Context, key string) error {
+	return m.lockContext(ctx, key, false)
+}
+
+// RUnlock the mutex for the given key. Caller must have called RLock for the same key first
+func (m *MutexKV) RUnlock(key string) {
+	log.Printf("[DEBUG] Read unlocking %q", key)
+	m.get(key).unlock(false)
+	log.Printf("[DEBUG] Read unlocked %q", key)
+}
+
+func (m *MutexKV) lockContext(ctx context.Context, key string, write bool) error {
+	holder := newMutexHolder(ctx, write)
+	mode := "read locking"
+	if write {
+		mode = "locking"
+	}
+
+	log.Printf("[DEBUG] Started %s %q for %s", mode, key, holder.debugId)
+	if err := m.get(key).lock(ctx, key, holder); err != nil {
+		return fmt.Errorf("error %s %q: %v", mode, key, err)
+	}
+	log.Printf("[DEBUG] Locked %q for %s", key, holder.debugId)
+	return nil
+}
+
 // Returns a mutex for the given key, no guarantee of its lock status
-func (m *MutexKV) get(key string) *sync.Mutex {
+func (m *MutexKV) get(key string) *keyMutex {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	mutex, ok := m.store[key]
 	if !ok {
-		mutex = &sync.Mutex{}
+		mutex = &keyMutex{changed: make(chan struct{})}
 		m.store[key] = mutex
 	}
 	return mutex
 }
 
+// StartWatchdog starts logging a warning for every key held for longer than
+// threshold, along with its holders. Later calls only change the threshold.
+func (m *MutexKV) StartWatchdog(threshold time.Duration) {
+	m.lock.Lock()
+	m.watchdogThreshold = threshold
+	m.lock.Unlock()
+
+	m.watchdogOnce.Do(func() {
+		go func() {
+			for {
+				m.lock.Lock()
+				threshold := m.watchdogThreshold
+				m.lock.Unlock()
+
+				time.Sleep(threshold)
+				m.logLongHeldKeys(threshold)
+			}
+		}()
+	})
+}
+
+func (m *MutexKV) logLongHeldKeys(threshold time.Duration) {
+	m.lock.Lock()
+	keys := make(map[string]*keyMutex, len(m.store))
+	for k, v := range m.store {
+		keys[k] = v
+	}
+	m.lock.Unlock()
+
+	for key, mutex := range keys {
+		holders, waiting := mutex.longHeld(threshold)
+		if len(holders) == 0 {
+			continue
+		}
+		var held []string
+		for _, h := range holders {
+			held = append(held, h.String())
+		}
+		log.Printf("[WARN] Key %q has been held longer than %s by %s, with writers waiting: %t", key, threshold, strings.Join(held, ", "), waiting)
+	}
+}
+
+func (km *keyMutex) lock(ctx context.Context, key string, holder mutexHolder) error {
+	km.mutex.Lock()
+	if holder.write {
+		km.writersWaiting++
+	}
+	warnedDeadlock := false
+	for {
+		available := !km.writer && (holder.write && km.readers == 0 || !holder.write && km.writersWaiting == 0)
+		if available {
+			if holder.write {
+				km.writersWaiting--
+				km.writer = true
+			} else {
+				km.readers++
+			}
+			holder.acquired = time.Now()
+			km.holders = append(km.holders, holder)
+			km.mutex.Unlock()
+			return nil
+		}
+
+		// Locking a key again from the same holder while it has the key locked
+		// for writing will never succeed. Holders identified by location may
+		// be different callers, so are not checked. Warn once per wait rather
+		// than on every wakeup.
+		for _, h := range km.holders {
+			if !warnedDeadlock && holder.named && h.write && h.debugId == holder.debugId {
+				log.Printf("[WARN] Possible deadlock: %s is waiting for %q, which it already holds", holder.debugId, key)
+				warnedDeadlock = true
+			}
+		}
+
+		changed := km.changed
+		km.mutex.Unlock()
+
+		select {
+		case <-changed:
+			km.mutex.Lock()
+		case <-ctx.Done():
+			km.mutex.Lock()
+			if holder.write {
+				km.writersWaiting--
+				// Readers may have been waiting behind this writer.
+				km.broadcast()
+			}
+			holders := km.holderStrings()
+			km.mutex.Unlock()
+			return fmt.Errorf("%v while waiting for holders %s", ctx.Err(), holders)
+		}
+	}
+}
+
+func (km *keyMutex) unlock(write bool) {
+	km.mutex.Lock()
+	defer km.mutex.Unlock()
+
+	if write {
+		if !km.writer {
+			panic("unlock of unlocked MutexKV key")
+		}
+		km.writer = false
+	} else {
+		if km.readers == 0 {
+			panic("read unlock of unlocked MutexKV key")
+		}
+		km.readers--
+	}
+
+	// Readers can't be told apart when unlocking, so remove the one that has
+	// held the key the longest.
+	for i, h := range km.holders {
+		if h.write == write {
+			km.holders = append(km.holders[:i], km.holders[i+1:]...)
+			break
+		}
+	}
+	km.broadcast()
+}
+
+// broadcast wakes all waiting callers. km.mutex must be held.
+func (km *keyMutex) broadcast() {
+	close(km.changed)
+	km.changed = make(chan struct{})
+}
+
+// holderStrings describes the current holders. km.mutex must be held.
+func (km *keyMutex) holderStrings() string {
+	if len(km.holders) == 0 {
+		return "[]"
+	}
+	var held []string
+	for _, h := range km.holders {
+		held = append(held, h.String())
+	}
+	return "[" + strings.Join(held, ", ") + "]"
+}
+
+// longHeld returns the holders that have held the key for longer than
+// threshold, and whether writers are waiting for the key.
+func (km *keyMutex) longHeld(threshold time.Duration) ([]mutexHolder, bool) {
+	km.mutex.Lock()
+	defer km.mutex.Unlock()
+
+	var holders []mutexHolder
+	for _, h := range km.holders {
+		if time.Since(h.acquired) > threshold {
+			holders = append(holders, h)
+		}
+	}
+	return holders, km.writersWaiting > 0
+}
+
 // Returns a properly initialized MutexKV
 func NewMutexKV() *MutexKV {
 	return &MutexKV{
-		store: make(map[string]*sync.Mutex),
+		store: make(map[string]*keyMutex),
 	}
 }
@@ -0,0 +1,158 @@
+package google
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMutexKV_LockContextTimeout(t *testing.T) {
+	m := NewMutexKV()
+	if err := m.LockContext(ContextWithMutexHolder(context.Background(), "holder"), "key"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer m.Unlock("key")
+
+	ctx, cc := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cc()
+	err := m.LockContext(ctx, "key")
+	if err == nil {
+		t.Fatalf("expected error locking a held key past the context deadline")
+	}
+	if !strings.Contains(err.Error(), "holder (write") {
+		t.Errorf("expected error %q to describe the holder of the key", err)
+	}
+}
+
+func TestMutexKV_resourceMutexContext(t *testing.T) {
+	m := NewMutexKV()
+	ctx, cancel := resourceMutexContext("ComputeNetwork update", "projects/p/global/networks/n", time.Minute)
+	defer cancel()
+	if err := m.LockContext(ctx, "key"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer m.Unlock("key")
+
+	ctx, cancel = resourceMutexContext("ComputeNetwork delete", "", 100*time.Millisecond)
+	defer cancel()
+	err := m.LockContext(ctx, "key")
+	if err == nil {
+		t.Fatalf("expected error locking a held key past the resource timeout")
+	}
+	if !strings.Contains(err.Error(), `ComputeNetwork update "projects/p/global/networks/n" (write`) {
+		t.Errorf("expected error %q to describe the resource holding the key", err)
+	}
+}
+
+func TestMutexKV_ReadersShareKey(t *testing.T) {
+	m := NewMutexKV()
+	m.RLock("key")
+	defer m.RUnlock("key")
+
+	ctx, cc := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cc()
+	if err := m.RLockContext(ctx, "key"); err != nil {
+		t.Fatalf("expected a second reader to lock the key, got error: %v", err)
+	}
+	m.RUnlock("key")
+
+	ctx, cc = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cc()
+	if err := m.LockContext(ctx, "key"); err == nil {
+		t.Fatalf("expected a writer not to lock a key held by a reader")
+	}
+}
+
+func TestMutexKV_WaitingWriterBlocksReaders(t *testing.T) {
+	m := NewMutexKV()
+	m.RLock("key")
+
+	locked := make(chan struct{})
+	go func() {
+		m.Lock("key")
+		close(locked)
+	}()
+
+	// Wait for the writer to start waiting.
+	for {
+		km := m.get("key")
+		km.mutex.Lock()
+		waiting := km.writersWaiting
+		km.mutex.Unlock()
+		if waiting > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cc := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cc()
+	if err := m.RLockContext(ctx, "key"); err == nil {
+		t.Fatalf("expected a reader not to lock a key a writer is waiting for")
+	}
+
+	m.RUnlock("key")
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the writer to lock the key once the reader unlocked it")
+	}
+	m.Unlock("key")
+}
+
+func TestMutexKV_UnlockReleasesKey(t *testing.T) {
+	m := NewMutexKV()
+	m.Lock("key")
+	m.Unlock("key")
+
+	ctx, cc := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cc()
+	if err := m.LockContext(ctx, "key"); err != nil {
+		t.Fatalf("expected to lock an unlocked key, got error: %v", err)
+	}
+	m.Unlock("key")
+
+	km := m.get("key")
+	if len(km.holders) != 0 {
+		t.Errorf("expected no holders of an unlocked key, got %v", km.holders)
+	}
+}
+
+func TestMutexKV_WarnsOfDeadlockOnce(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	m := NewMutexKV()
+	ctx := ContextWithMutexHolder(context.Background(), "holder")
+	if err := m.LockContext(ctx, "key"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer m.Unlock("key")
+
+	ctx, cc := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cc()
+	done := make(chan error)
+	go func() {
+		done <- m.LockContext(ctx, "key")
+	}()
+
+	// Wake the waiting caller a few times without releasing the key.
+	for i := 0; i < 3; i++ {
+		time.Sleep(20 * time.Millisecond)
+		km := m.get("key")
+		km.mutex.Lock()
+		km.broadcast()
+		km.mutex.Unlock()
+	}
+	if err := <-done; err == nil {
+		t.Fatalf("expected error locking a key the holder already holds")
+	}
+	if n := strings.Count(buf.String(), "Possible deadlock"); n != 1 {
+		t.Errorf("expected the possible deadlock to be logged once, got %d times", n)
+	}
+}
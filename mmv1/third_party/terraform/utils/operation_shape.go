@@ -0,0 +1,230 @@
+package google
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// OperationShape describes where an API's operations keep their name, status,
+// error, result and progress, like the async block of a product's api.yaml.
+// ShapedOperationWaiter uses it to wait for operations of any API.
+//
+// Paths are dot separated JSON field names, such as "error.errors".
+type OperationShape struct {
+	NamePath string
+
+	// StatusPath is the path of the operation status. The operation is done
+	// once the status, formatted with %v, is CompleteStatus. A missing status
+	// means the operation is still running.
+	StatusPath     string
+	CompleteStatus string
+
+	// ErrorPath is the path of the error of a failed operation. Errors with a
+	// "message" field, lists of them and strings are understood.
+	ErrorPath string
+	// ErrorWhenComplete means the field at ErrorPath is only an error once the
+	// operation is done, as it holds a status message while it's running.
+	ErrorWhenComplete bool
+	// ErrorF converts the value at ErrorPath to an error, if set.
+	ErrorF func(v interface{}) error
+
+	// ResultPath is the path of the result of a successful operation.
+	ResultPath string
+
+	// ProgressPaths are the paths of fields reporting the progress of the
+	// operation, which are logged while waiting for it.
+	ProgressPaths []string
+}
+
+// LongRunningOperationShape is the shape of google.longrunning.Operation,
+// which most APIs use. Errors are *CommonOpErrors, as with
+// CommonOperationWaiter.
+var LongRunningOperationShape = &OperationShape{
+	NamePath:       "name",
+	StatusPath:     "done",
+	CompleteStatus: "true",
+	ErrorPath:      "error",
+	ErrorF: func(v interface{}) error {
+		status := &cloudresourcemanager.Status{}
+		if err := Convert(v, status); err != nil {
+			return err
+		}
+		return &CommonOpError{status}
+	},
+	ResultPath:    "response",
+	ProgressPaths: []string{"metadata"},
+}
+
+// The states a ShapedOperationWaiter reports, whatever the operation status.
+const (
+	shapedOperationPending = "pending"
+	shapedOperationDone    = "done"
+)
+
+// ShapedOperationWaiter is a Waiter for operations of any API, described by
+// Shape. The operation is held as a map, so any operation type can be set.
+type ShapedOperationWaiter struct {
+	Shape *OperationShape
+	// QueryF gets the current state of the operation with the given name.
+	QueryF func(name string) (interface{}, error)
+
+	Op map[string]interface{}
+}
+
+// Field returns the value at path in the operation, or nil if there is none.
+func (w *ShapedOperationWaiter) Field(path string) interface{} {
+	if w == nil || path == "" {
+		return nil
+	}
+	var v interface{} = w.Op
+	for _, k := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+func (w *ShapedOperationWaiter) done() bool {
+	status := w.Field(w.Shape.StatusPath)
+	return status != nil && fmt.Sprintf("%v", status) == w.Shape.CompleteStatus
+}
+
+func (w *ShapedOperationWaiter) State() string {
+	if w == nil || w.Op == nil {
+		return "Operation is nil!"
+	}
+	if w.done() {
+		return shapedOperationDone
+	}
+	return shapedOperationPending
+}
+
+func (w *ShapedOperationWaiter) Error() error {
+	if w == nil || w.Op == nil {
+		return nil
+	}
+	if w.Shape.ErrorWhenComplete && !w.done() {
+		return nil
+	}
+	v := w.Field(w.Shape.ErrorPath)
+	if v == nil {
+		return nil
+	}
+	if w.Shape.ErrorF != nil {
+		return w.Shape.ErrorF(v)
+	}
+	return operationErrorFromValue(v)
+}
+
+func (w *ShapedOperationWaiter) IsRetryable(error) bool {
+	return false
+}
+
+func (w *ShapedOperationWaiter) SetOp(op interface{}) error {
+	if op == nil {
+		return fmt.Errorf("Unable to set operation, it's nil.")
+	}
+	opMap, err := ConvertToMap(op)
+	if err != nil {
+		return err
+	}
+	w.Op = opMap
+	return nil
+}
+
+func (w *ShapedOperationWaiter) QueryOp() (interface{}, error) {
+	if w == nil || w.Op == nil {
+		return nil, fmt.Errorf("Cannot query operation, it's unset or nil.")
+	}
+	return w.QueryF(w.OpName())
+}
+
+func (w *ShapedOperationWaiter) OpName() string {
+	if w == nil {
+		return "<nil>"
+	}
+	name, _ := w.Field(w.Shape.NamePath).(string)
+	return name
+}
+
+func (w *ShapedOperationWaiter) PendingStates() []string {
+	return []string{shapedOperationPending}
+}
+
+func (w *ShapedOperationWaiter) TargetStates() []string {
+	return []string{shapedOperationDone}
+}
+
+// Progress describes the fields at Shape.ProgressPaths, and the raw status.
+func (w *ShapedOperationWaiter) Progress() string {
+	var progress []string
+	if status := w.Field(w.Shape.StatusPath); status != nil {
+		progress = append(progress, fmt.Sprintf("%s: %v", w.Shape.StatusPath, status))
+	}
+	for _, path := range w.Shape.ProgressPaths {
+		v := w.Field(path)
+		if v == nil {
+			continue
+		}
+		if s, ok := v.(string); ok {
+			progress = append(progress, fmt.Sprintf("%s: %s", path, s))
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		progress = append(progress, fmt.Sprintf("%s: %s", path, b))
+	}
+	return strings.Join(progress, ", ")
+}
+
+// Result returns the value at Shape.ResultPath, or nil if there is none.
+func (w *ShapedOperationWaiter) Result() interface{} {
+	return w.Field(w.Shape.ResultPath)
+}
+
+// ResultJSON returns the value at Shape.ResultPath as JSON, or nil if there is
+// none.
+func (w *ShapedOperationWaiter) ResultJSON() ([]byte, error) {
+	result := w.Result()
+	if result == nil {
+		return nil, nil
+	}
+	return json.Marshal(result)
+}
+
+// operationErrorFromValue formats the error field of an operation.
+func operationErrorFromValue(v interface{}) error {
+	switch e := v.(type) {
+	case string:
+		if e == "" {
+			return nil
+		}
+		return errors.New(e)
+	case map[string]interface{}:
+		if code, ok := e["code"]; ok {
+			return fmt.Errorf("Error code %v, message: %v", code, e["message"])
+		}
+		return fmt.Errorf("%v", e["message"])
+	case []interface{}:
+		if len(e) == 0 {
+			return nil
+		}
+		var msgs []string
+		for _, item := range e {
+			if err := operationErrorFromValue(item); err != nil {
+				msgs = append(msgs, err.Error())
+			}
+		}
+		return errors.New(strings.Join(msgs, "\n"))
+	}
+	return fmt.Errorf("%v", v)
+}
@@ -0,0 +1,240 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// fakeOperationQuery returns a QueryF that returns each of ops in turn, then
+// the last one forever.
+func fakeOperationQuery(ops ...map[string]interface{}) func(string) (interface{}, error) {
+	i := 0
+	return func(name string) (interface{}, error) {
+		if name != "operations/my-op" {
+			return nil, fmt.Errorf("unexpected operation name %q", name)
+		}
+		op := ops[i]
+		if i < len(ops)-1 {
+			i++
+		}
+		return op, nil
+	}
+}
+
+func TestShapedOperationWaiter_LongRunning(t *testing.T) {
+	w := &ShapedOperationWaiter{
+		Shape: LongRunningOperationShape,
+		QueryF: fakeOperationQuery(
+			map[string]interface{}{"name": "operations/my-op", "metadata": map[string]interface{}{"progressPercent": 50}},
+			map[string]interface{}{"name": "operations/my-op", "done": true, "response": map[string]interface{}{"id": "my-resource"}},
+		),
+	}
+	if err := w.SetOp(map[string]interface{}{"name": "operations/my-op"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := OperationWait(w, "Creating Resource", time.Second, 10*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{"id": "my-resource"}
+	if got := w.Result(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected result %v, got %v", expected, got)
+	}
+}
+
+func TestShapedOperationWaiter_Error(t *testing.T) {
+	listShape := &OperationShape{
+		NamePath:       "name",
+		StatusPath:     "status",
+		CompleteStatus: "DONE",
+		ErrorPath:      "error.errors",
+	}
+
+	cases := map[string]struct {
+		shape    *OperationShape
+		op       map[string]interface{}
+		expected string
+	}{
+		"longRunning": {
+			shape:    LongRunningOperationShape,
+			op:       map[string]interface{}{"name": "operations/my-op", "done": true, "error": map[string]interface{}{"code": 3, "message": "bad request"}},
+			expected: "Error code 3, message: bad request",
+		},
+		"list": {
+			shape: listShape,
+			op: map[string]interface{}{"name": "operations/my-op", "status": "DONE", "error": map[string]interface{}{"errors": []interface{}{
+				map[string]interface{}{"message": "first"},
+				map[string]interface{}{"message": "second"},
+			}}},
+			expected: "first\nsecond",
+		},
+		"statusMessageWhileRunning": {
+			shape:    ContainerOperationShape,
+			op:       map[string]interface{}{"name": "operations/my-op", "status": "RUNNING", "statusMessage": "creating nodes"},
+			expected: "",
+		},
+		"statusMessageWhenDone": {
+			shape:    ContainerOperationShape,
+			op:       map[string]interface{}{"name": "operations/my-op", "status": "DONE", "statusMessage": "quota exceeded"},
+			expected: "quota exceeded",
+		},
+	}
+	for tn, tc := range cases {
+		w := &ShapedOperationWaiter{Shape: tc.shape}
+		if err := w.SetOp(tc.op); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tn, err)
+		}
+		err := w.Error()
+		if tc.expected == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", tn, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.expected {
+			t.Errorf("%s: expected error %q, got %v", tn, tc.expected, err)
+		}
+		if _, ok := err.(*CommonOpError); tc.shape == LongRunningOperationShape && !ok {
+			t.Errorf("%s: expected *CommonOpError, got %T", tn, err)
+		}
+	}
+}
+
+func TestShapedOperationWaiter_Progress(t *testing.T) {
+	w := &ShapedOperationWaiter{Shape: ContainerOperationShape}
+	err := w.SetOp(map[string]interface{}{
+		"name":          "operations/my-op",
+		"status":        "RUNNING",
+		"operationType": "CREATE_CLUSTER",
+		"progress": map[string]interface{}{
+			"metrics": []interface{}{map[string]interface{}{"name": "nodes_done", "intValue": "1"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `status: RUNNING, operationType: CREATE_CLUSTER, progress.metrics: [{"intValue":"1","name":"nodes_done"}]`
+	if got := w.Progress(); got != expected {
+		t.Errorf("expected progress %q, got %q", expected, got)
+	}
+}
+
+func TestOperationWaitResumable(t *testing.T) {
+	d := &ResourceDataMock{FieldsInSchema: map[string]interface{}{}}
+	running := map[string]interface{}{"name": "operations/my-op"}
+	w := &ShapedOperationWaiter{
+		Shape:  LongRunningOperationShape,
+		QueryF: fakeOperationQuery(running),
+	}
+	if err := w.SetOp(running); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := OperationWaitResumable(d, w, "Creating Resource", 50*time.Millisecond, 10*time.Millisecond)
+	if _, ok := err.(*PendingOperationError); !ok {
+		t.Fatalf("expected *PendingOperationError, got %v", err)
+	}
+	if got := d.Get(pendingOperationKey); got != "operations/my-op" {
+		t.Fatalf("expected pending operation %q to be saved, got %v", "operations/my-op", got)
+	}
+}
+
+// testPendingOperationResource returns a resource whose Update resumes its
+// pending operation with a waiter that queries ops.
+func testPendingOperationResource(ops ...map[string]interface{}) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":              {Type: schema.TypeString, Optional: true},
+			pendingOperationKey: pendingOperationSchema(),
+		},
+		CustomizeDiff: resumePendingOperationDiff,
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			w := &ShapedOperationWaiter{
+				Shape:  LongRunningOperationShape,
+				QueryF: fakeOperationQuery(ops...),
+			}
+			return ResumePendingOperation(d, w, "Updating Resource", 50*time.Millisecond, 10*time.Millisecond)
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+	}
+}
+
+// Check that a plan and apply without other changes resumes the pending
+// operation.
+func TestResumePendingOperation(t *testing.T) {
+	running := map[string]interface{}{"name": "operations/my-op"}
+	failed := map[string]interface{}{"name": "operations/my-op", "done": true, "error": map[string]interface{}{"code": 13, "message": "internal"}}
+	cases := map[string]struct {
+		ops      []map[string]interface{}
+		err      string
+		expected string
+	}{
+		"done": {
+			ops:      []map[string]interface{}{running, failed},
+			err:      "internal",
+			expected: "",
+		},
+		"timesOutAgain": {
+			ops:      []map[string]interface{}{running},
+			err:      "still running",
+			expected: "operations/my-op",
+		},
+	}
+	for tn, tc := range cases {
+		r := testPendingOperationResource(tc.ops...)
+		state := &terraform.InstanceState{
+			ID: "my-resource",
+			Attributes: map[string]string{
+				"id":                "my-resource",
+				"name":              "my-resource",
+				pendingOperationKey: "operations/my-op",
+			},
+		}
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "my-resource"})
+
+		diff, err := r.SimpleDiff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tn, err)
+		}
+		if diff == nil || diff.Empty() {
+			t.Fatalf("%s: expected a plan to resume the pending operation", tn)
+		}
+
+		newState, diags := r.Apply(context.Background(), state, diff, nil)
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.err) {
+			t.Errorf("%s: expected error containing %q, got %v", tn, tc.err, diags)
+		}
+		if got := newState.Attributes[pendingOperationKey]; got != tc.expected {
+			t.Errorf("%s: expected pending operation %q, got %q", tn, tc.expected, got)
+		}
+	}
+
+	// Nothing to resume.
+	r := &schema.Resource{Schema: map[string]*schema.Schema{pendingOperationKey: pendingOperationSchema()}}
+	d := r.TestResourceData()
+	w := &ShapedOperationWaiter{
+		Shape: LongRunningOperationShape,
+		QueryF: func(string) (interface{}, error) {
+			return nil, fmt.Errorf("unexpected query")
+		},
+	}
+	if err := ResumePendingOperation(d, w, "Creating Resource", time.Second, 10*time.Millisecond); err != nil {
+		t.Errorf("expected no error without a pending operation, got %v", err)
+	}
+}
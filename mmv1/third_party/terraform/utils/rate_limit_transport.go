@@ -0,0 +1,175 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimit limits the rate of requests made to an API host, and optionally to
+// a single method of that API. It is configured by the provider-level
+// rate_limit blocks.
+type rateLimit struct {
+	host string
+	// method is the custom method of the request path, such as "setIamPolicy"
+	// in "/v1/projects/my-project:setIamPolicy". If empty, the limit applies
+	// to every request to host.
+	method string
+	// requestsPerSecond is the rate tokens are added to the bucket at.
+	requestsPerSecond float64
+	// burst is the maximum number of requests that can be made at once.
+	burst int
+}
+
+func (l *rateLimit) String() string {
+	if l.method == "" {
+		return l.host
+	}
+	return l.host + ":" + l.method
+}
+
+// tokenBucket is a token bucket rate limiter. The bucket starts full.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token from the bucket, and returns how long the caller must
+// wait before the token is available. The bucket may go into debt, so
+// concurrent callers are queued in order.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used.
+func (b *tokenBucket) cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.tokens++
+}
+
+// wait blocks until a token is available or ctx is done, and returns how long
+// it waited.
+func (b *tokenBucket) wait(ctx context.Context) (time.Duration, error) {
+	d := b.reserve()
+	if d == 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return 0, ctx.Err()
+	case <-timer.C:
+		return d, nil
+	}
+}
+
+type rateLimitTransport struct {
+	limits   []*rateLimit
+	buckets  map[*rateLimit]*tokenBucket
+	internal http.RoundTripper
+}
+
+// newTransportWithRateLimits wraps t with a transport that delays requests to
+// stay within limits. If there are no limits, t is returned as-is.
+func newTransportWithRateLimits(t http.RoundTripper, limits []*rateLimit) http.RoundTripper {
+	if len(limits) == 0 {
+		return t
+	}
+
+	buckets := make(map[*rateLimit]*tokenBucket)
+	for _, l := range limits {
+		buckets[l] = newTokenBucket(l.requestsPerSecond, l.burst)
+	}
+	return &rateLimitTransport{
+		limits:   limits,
+		buckets:  buckets,
+		internal: t,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+// It waits for the rate limit matching the request, if any, before making it.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limit := t.limitFor(req)
+	if limit != nil {
+		waited, err := t.buckets[limit].wait(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("waiting for rate limit %s: %w", limit, err)
+		}
+		if waited > 0 {
+			log.Printf("[DEBUG] Rate Limit Transport: throttled request to %s locally for %s by rate limit %s", req.URL, waited, limit)
+		}
+	}
+
+	resp, err := t.internal.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		log.Printf("[DEBUG] Rate Limit Transport: request to %s was throttled by the server", req.URL)
+	}
+	return resp, err
+}
+
+// limitFor returns the rate limit that applies to req. A limit for the
+// request's method takes precedence over a limit for its whole host.
+func (t *rateLimitTransport) limitFor(req *http.Request) *rateLimit {
+	method := requestCustomMethod(req)
+
+	var hostLimit *rateLimit
+	for _, l := range t.limits {
+		if l.host != req.URL.Host {
+			continue
+		}
+		if l.method == "" {
+			if hostLimit == nil {
+				hostLimit = l
+			}
+		} else if l.method == method {
+			return l
+		}
+	}
+	return hostLimit
+}
+
+// requestCustomMethod returns the custom method of a request path, or empty if
+// there is none.
+func requestCustomMethod(req *http.Request) string {
+	path := req.URL.Path
+	i := strings.LastIndex(path, ":")
+	if i < 0 || i < strings.LastIndex(path, "/") {
+		return ""
+	}
+	return path[i+1:]
+}
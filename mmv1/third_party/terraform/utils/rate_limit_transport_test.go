@@ -0,0 +1,122 @@
+package google
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func setUpRateLimitTransportServerClient(limits func(host string) []*rateLimit) (*httptest.Server, *http.Client) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	u, _ := url.Parse(ts.URL)
+	client := ts.Client()
+	client.Transport = newTransportWithRateLimits(http.DefaultTransport, limits(u.Host))
+	return ts, client
+}
+
+func TestRateLimitTransport_ThrottlesRequests(t *testing.T) {
+	ts, client := setUpRateLimitTransportServerClient(func(host string) []*rateLimit {
+		return []*rateLimit{{host: host, requestsPerSecond: 10, burst: 1}}
+	})
+	defer ts.Close()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// The first request uses the burst, and the remaining 4 wait 100ms each.
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expected 5 requests at 10 per second to take at least 400ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransport_DoesNotThrottleOtherHosts(t *testing.T) {
+	ts, client := setUpRateLimitTransportServerClient(func(host string) []*rateLimit {
+		return []*rateLimit{{host: "iam.googleapis.com", requestsPerSecond: 1, burst: 1}}
+	})
+	defer ts.Close()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected requests to an unlimited host not to be throttled, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransport_ContextCancelled(t *testing.T) {
+	ts, client := setUpRateLimitTransportServerClient(func(host string) []*rateLimit {
+		return []*rateLimit{{host: host, requestsPerSecond: 0.1, burst: 1}}
+	})
+	defer ts.Close()
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	ctx, cc := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cc()
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	if _, err := client.Do(req); err == nil {
+		t.Fatalf("expected error waiting for rate limit past the context deadline")
+	}
+}
+
+func TestRateLimitTransport_limitFor(t *testing.T) {
+	hostLimit := &rateLimit{host: "cloudresourcemanager.googleapis.com", requestsPerSecond: 1, burst: 1}
+	methodLimit := &rateLimit{host: "cloudresourcemanager.googleapis.com", method: "setIamPolicy", requestsPerSecond: 1, burst: 1}
+	transport := newTransportWithRateLimits(http.DefaultTransport, []*rateLimit{hostLimit, methodLimit}).(*rateLimitTransport)
+
+	cases := map[string]struct {
+		url      string
+		expected *rateLimit
+	}{
+		"method": {
+			url:      "https://cloudresourcemanager.googleapis.com/v1/projects/my-project:setIamPolicy",
+			expected: methodLimit,
+		},
+		"otherMethod": {
+			url:      "https://cloudresourcemanager.googleapis.com/v1/projects/my-project:getIamPolicy",
+			expected: hostLimit,
+		},
+		"noMethod": {
+			url:      "https://cloudresourcemanager.googleapis.com/v1/projects/my-project",
+			expected: hostLimit,
+		},
+		"otherHost": {
+			url:      "https://iam.googleapis.com/v1/projects/my-project:setIamPolicy",
+			expected: nil,
+		},
+	}
+	for tn, tc := range cases {
+		req, err := http.NewRequest("POST", tc.url, nil)
+		if err != nil {
+			t.Fatalf("%s: unable to construct request: %v", tn, err)
+		}
+		if got := transport.limitFor(req); got != tc.expected {
+			t.Errorf("%s: expected rate limit %v, got %v", tn, tc.expected, got)
+		}
+	}
+}
@@ -15,13 +15,13 @@
 // Example Usage in Terraform Config:
 //	client := oauth2.NewClient(ctx, tokenSource)
 //	// Create with default retry predicates
-//	client.Transport := NewTransportWithDefaultRetries(client.Transport, defaultTimeout)
+//	client.Transport := NewTransportWithDefaultRetries(client.Transport, c.RetryConfig)
 //
 //	// If API uses just default retry predicates:
 //	c.clientCompute, err = compute.NewService(ctx, option.WithHTTPClient(client))
 //	...
 //	// If API needs custom additional retry predicates:
-//	sqlAdminHttpClient := ClientWithAdditionalRetries(client, retryTransport,
+//	sqlAdminHttpClient := ClientWithAdditionalRetries(client, c.RetryConfig,
 //			isTemporarySqlError1,
 //			isTemporarySqlError2)
 //	c.clientSqlAdmin, err = compute.NewService(ctx, option.WithHTTPClient(sqlAdminHttpClient))
@@ -36,8 +36,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -45,20 +47,47 @@ import (
 )
 
 const defaultRetryTransportTimeoutSec = 90
+const defaultRetryTransportInitialBackoffMs = 500
+const defaultRetryTransportMaxBackoffSec = 30
 
-// NewTransportWithDefaultRetries constructs a default retryTransport that will retry common temporary errors
-func NewTransportWithDefaultRetries(t http.RoundTripper) *retryTransport {
+// retryConfig bounds the retries made by a retryTransport. It is configured by
+// the provider-level retry block.
+type retryConfig struct {
+	// timeout is the total time spent retrying a request, unless the request
+	// context already has a deadline.
+	timeout time.Duration
+	// initialBackoff is the upper bound of the wait before the first retry.
+	// The bound doubles for each following retry, up to maxBackoff.
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	// maxAttempts is the maximum number of requests made, or 0 for no limit
+	// other than timeout.
+	maxAttempts int
+}
+
+func defaultRetryConfig() *retryConfig {
+	return &retryConfig{
+		timeout:        defaultRetryTransportTimeoutSec * time.Second,
+		initialBackoff: defaultRetryTransportInitialBackoffMs * time.Millisecond,
+		maxBackoff:     defaultRetryTransportMaxBackoffSec * time.Second,
+	}
+}
+
+// NewTransportWithDefaultRetries constructs a default retryTransport that will retry common temporary errors.
+// If config is nil, the default retry config is used.
+func NewTransportWithDefaultRetries(t http.RoundTripper, config *retryConfig) *retryTransport {
 	return &retryTransport{
 		retryPredicates: defaultErrorRetryPredicates,
 		internal:        t,
+		config:          config,
 	}
 }
 
 // Helper method to create a shallow copy of an HTTP client with a shallow-copied retryTransport
 // s.t. the base HTTP transport is the same (i.e. client connection pools are shared, retryPredicates are different)
-func ClientWithAdditionalRetries(baseClient *http.Client, predicates ...RetryErrorPredicateFunc) *http.Client {
+func ClientWithAdditionalRetries(baseClient *http.Client, config *retryConfig, predicates ...RetryErrorPredicateFunc) *http.Client {
 	copied := *baseClient
-	baseRetryTransport := NewTransportWithDefaultRetries(baseClient.Transport)
+	baseRetryTransport := NewTransportWithDefaultRetries(baseClient.Transport, config)
 	copied.Transport = baseRetryTransport.WithAddedPredicates(predicates...)
 	return &copied
 }
@@ -74,17 +103,30 @@ func (t *retryTransport) WithAddedPredicates(predicates ...RetryErrorPredicateFu
 type retryTransport struct {
 	retryPredicates []RetryErrorPredicateFunc
 	internal        http.RoundTripper
+	config          *retryConfig
+}
+
+func (t *retryTransport) retryConfig() *retryConfig {
+	if t.config == nil {
+		return defaultRetryConfig()
+	}
+	return t.config
 }
 
 // RoundTrip implements the RoundTripper interface method.
 // It retries the given HTTP request based on the retry predicates
 // registered under the retryTransport.
 func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, respErr error) {
-	// Set timeout to default value.
+	config := t.retryConfig()
+
+	// Trace every attempt at the request with the same debug id.
+	req = req.WithContext(contextWithTraceRequest(req.Context()))
+
+	// Set timeout to configured value.
 	ctx := req.Context()
 	var ccancel context.CancelFunc
 	if _, ok := ctx.Deadline(); !ok {
-		ctx, ccancel = context.WithTimeout(ctx, defaultRetryTransportTimeoutSec*time.Second)
+		ctx, ccancel = context.WithTimeout(ctx, config.timeout)
 		defer func() {
 			if ctx.Err() == nil {
 				// Cleanup child context created for retry loop if ctx not done.
@@ -94,8 +136,6 @@ func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, resp
 	}
 
 	attempts := 0
-	backoff := time.Millisecond * 500
-	nextBackoff := time.Millisecond * 500
 
 	// VCR depends on the original request body being consumed, so
 	// consume here. Since this won't affect the request itself,
@@ -115,12 +155,13 @@ Retry:
 		if copyErr != nil {
 			log.Printf("[WARN] Retry Transport: Unable to copy request body: %v.", copyErr)
 			log.Printf("[WARN] Retry Transport: Running request as non-retryable")
-			resp, respErr = t.internal.RoundTrip(req)
+			resp, respErr = t.internal.RoundTrip(req.WithContext(contextWithTraceAttempt(req.Context(), 1)))
 			break Retry
 		}
 
 		log.Printf("[DEBUG] Retry Transport: request attempt %d", attempts)
 		// Do the wrapped Roundtrip. This is one request in the retry loop.
+		newRequest = newRequest.WithContext(contextWithTraceAttempt(newRequest.Context(), attempts+1))
 		resp, respErr = t.internal.RoundTrip(newRequest)
 		attempts++
 
@@ -133,6 +174,26 @@ Retry:
 			log.Printf("[DEBUG] Retry Transport: Stopping retries, last request failed with non-retryable error: %s", retryErr.Err)
 			break Retry
 		}
+		if config.maxAttempts > 0 && attempts >= config.maxAttempts {
+			log.Printf("[DEBUG] Retry Transport: Stopping retries, reached maximum of %d attempts", config.maxAttempts)
+			break Retry
+		}
+
+		// Prefer the delay requested by the server over our own backoff, but
+		// don't let the server hold us past maxBackoff or the deadline.
+		backoff, ok := serverRetryDelay(resp, retryErr.Err)
+		if !ok {
+			backoff = config.backoff(attempts)
+		} else if backoff > config.maxBackoff {
+			backoff = config.maxBackoff
+		}
+		untilDeadline := false
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline); backoff >= remaining {
+				backoff = remaining
+				untilDeadline = true
+			}
+		}
 
 		log.Printf("[DEBUG] Retry Transport: Waiting %s before trying request again", backoff)
 		select {
@@ -140,12 +201,13 @@ Retry:
 			log.Printf("[DEBUG] Retry Transport: Stopping retries, context done: %v", ctx.Err())
 			break Retry
 		case <-time.After(backoff):
+			// Don't start a request that can only fail with the context
+			// error, even if the context hasn't noticed its deadline yet.
+			if untilDeadline {
+				log.Printf("[DEBUG] Retry Transport: Stopping retries, reached context deadline")
+				break Retry
+			}
 			log.Printf("[DEBUG] Retry Transport: Finished waiting %s before next retry", backoff)
-
-			// Fibonnaci backoff - 0.5, 1, 1.5, 2.5, 4, 6.5, 10.5, ...
-			lastBackoff := backoff
-			backoff = backoff + nextBackoff
-			nextBackoff = lastBackoff
 			continue
 		}
 	}
@@ -153,6 +215,73 @@ Retry:
 	return resp, respErr
 }
 
+// backoff returns the wait before the retry following the given number of
+// attempts, using full jitter exponential backoff: a random duration between
+// zero and initialBackoff * 2^(attempts-1), capped at maxBackoff.
+func (c *retryConfig) backoff(attempts int) time.Duration {
+	ceiling := c.initialBackoff
+	for i := 1; i < attempts && ceiling < c.maxBackoff; i++ {
+		ceiling *= 2
+	}
+	if ceiling > c.maxBackoff {
+		ceiling = c.maxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// serverRetryDelay returns the delay requested by the server before retrying,
+// from either a Retry-After header on a 429 or 503 response or the RetryInfo
+// details of a googleapi.Error.
+func serverRetryDelay(resp *http.Response, err error) (time.Duration, bool) {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d, true
+		}
+	}
+
+	gerr, ok := err.(*googleapi.Error)
+	if !ok {
+		return 0, false
+	}
+	for _, detail := range gerr.Details {
+		m, ok := detail.(map[string]interface{})
+		if !ok || m["@type"] != "type.googleapis.com/google.rpc.RetryInfo" {
+			continue
+		}
+		if v, ok := m["retryDelay"].(string); ok {
+			if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+				return d, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
 // copyHttpRequest provides an copy of the given HTTP request for one RoundTrip.
 // If the request has a non-empty body (io.ReadCloser), the body is deep copied
 // so it can be consumed.
@@ -190,13 +319,15 @@ func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr err
 		// returned cannot be edited. We need to consume the Body to check for
 		// errors, so we need to create a copy if the Response has a body.
 		if resp.Body != nil && resp.Body != http.NoBody {
-			// Use httputil.DumpResponse since the only important info is
-			// error code and messages in the response body.
-			dumpBytes, err := httputil.DumpResponse(resp, true)
+			// Read the body and restore it, so that the error can be parsed from
+			// the JSON body, including any details such as RetryInfo.
+			bodyBytes, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
 			if err != nil {
 				return resource.NonRetryableError(fmt.Errorf("unable to check response for error: %v", err))
 			}
-			respToCheck.Body = ioutil.NopCloser(bytes.NewReader(dumpBytes))
+			resp.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
+			respToCheck.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
 		}
 		errToCheck = googleapi.CheckResponse(&respToCheck)
 	}
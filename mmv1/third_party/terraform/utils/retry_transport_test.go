@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -25,6 +26,12 @@ func setUpRetryTransportServerClient(hf http.Handler) (*httptest.Server, *http.C
 	client.Transport = &retryTransport{
 		internal:        http.DefaultTransport,
 		retryPredicates: []RetryErrorPredicateFunc{testRetryTransportRetryPredicate},
+		// Keep backoff short so jitter can't push a retry past test deadlines.
+		config: &retryConfig{
+			timeout:        defaultRetryTransportTimeoutSec * time.Second,
+			initialBackoff: 100 * time.Millisecond,
+			maxBackoff:     500 * time.Millisecond,
+		},
 	}
 	return ts, client
 }
@@ -168,6 +175,159 @@ func TestRetryTransport_DoesNotRetryEmptyGetBody(t *testing.T) {
 	testRetryTransport_checkFailedWhileRetrying(t, resp, err)
 }
 
+// Check that a Retry-After header is waited for instead of the backoff
+func TestRetryTransport_HonorsRetryAfter(t *testing.T) {
+	var attempts int32
+	cfg := testRetryTransportConfig_noBackoff()
+	cfg.maxBackoff = 2 * time.Second
+	ts, client := setUpRetryTransportServerClientWithConfig(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(testRetryTransportCodeSuccess)
+		}), cfg)
+	defer ts.Close()
+
+	start := time.Now()
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkSuccess(t, resp, err)
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait at least 1s for Retry-After, waited %s", elapsed)
+	}
+}
+
+// Check that the RetryInfo details of an error are waited for instead of the
+// backoff
+func TestRetryTransport_HonorsRetryInfo(t *testing.T) {
+	var attempts int32
+	cfg := testRetryTransportConfig_noBackoff()
+	cfg.maxBackoff = 2 * time.Second
+	ts, client := setUpRetryTransportServerClientWithConfig(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(testRetryTransportCodeRetry)
+				body := `{"error": {"code": 500, "message": "retry later", "details": [{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "1s"}]}}`
+				if _, err := w.Write([]byte(body)); err != nil {
+					t.Errorf("[ERROR] unable to write to response writer: %v", err)
+				}
+				return
+			}
+			w.WriteHeader(testRetryTransportCodeSuccess)
+		}), cfg)
+	defer ts.Close()
+
+	start := time.Now()
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkSuccess(t, resp, err)
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait at least 1s for RetryInfo, waited %s", elapsed)
+	}
+}
+
+// Check that retries stop once the configured maximum attempts are made
+func TestRetryTransport_MaxAttempts(t *testing.T) {
+	var attempts int32
+	cfg := testRetryTransportConfig_noBackoff()
+	cfg.maxAttempts = 3
+	ts, client := setUpRetryTransportServerClientWithConfig(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.WriteHeader(testRetryTransportCodeRetry)
+		}), cfg)
+	defer ts.Close()
+
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkFailedWhileRetrying(t, resp, err)
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+}
+
+// Check that a Retry-After longer than the maximum backoff is capped
+func TestRetryTransport_CapsRetryAfter(t *testing.T) {
+	var attempts int32
+	ts, client := setUpRetryTransportServerClientWithConfig(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(testRetryTransportCodeSuccess)
+		}), testRetryTransportConfig_noBackoff())
+	defer ts.Close()
+
+	start := time.Now()
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkSuccess(t, resp, err)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected Retry-After to be capped at the maximum backoff, waited %s", elapsed)
+	}
+}
+
+func TestRetryConfig_backoff(t *testing.T) {
+	cfg := &retryConfig{
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     time.Second,
+	}
+	cases := map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	}
+	for attempts, ceiling := range cases {
+		for i := 0; i < 100; i++ {
+			if d := cfg.backoff(attempts); d < 0 || d > ceiling {
+				t.Fatalf("expected backoff after %d attempts to be between 0 and %s, got %s", attempts, ceiling, d)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		"empty":    {value: "", ok: false},
+		"seconds":  {value: "5", expected: 5 * time.Second, ok: true},
+		"negative": {value: "-5", ok: false},
+		"past":     {value: "Wed, 21 Oct 2015 07:28:00 GMT", expected: 0, ok: true},
+		"invalid":  {value: "soon", ok: false},
+	}
+	for tn, tc := range cases {
+		d, ok := parseRetryAfter(tc.value)
+		if ok != tc.ok || d != tc.expected {
+			t.Errorf("%s: expected (%s, %t), got (%s, %t)", tn, tc.expected, tc.ok, d, ok)
+		}
+	}
+}
+
+func setUpRetryTransportServerClientWithConfig(hf http.Handler, cfg *retryConfig) (*httptest.Server, *http.Client) {
+	ts, client := setUpRetryTransportServerClient(hf)
+	rt := client.Transport.(*retryTransport)
+	rt.config = cfg
+	rt.retryPredicates = append(rt.retryPredicates, testRetryTransportRetryPredicate503)
+	return ts, client
+}
+
+// testRetryTransportConfig_noBackoff retries near-immediately, so any wait is
+// requested by the server.
+func testRetryTransportConfig_noBackoff() *retryConfig {
+	return &retryConfig{
+		timeout:        10 * time.Second,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     time.Millisecond,
+	}
+}
+
 // handlers
 func testRetryTransportHandler_noRetries(t *testing.T, code int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -304,3 +464,13 @@ func testRetryTransportRetryPredicate(err error) (bool, string) {
 	}
 	return false, ""
 }
+
+// Retries 503.
+func testRetryTransportRetryPredicate503(err error) (bool, string) {
+	if gerr, ok := err.(*googleapi.Error); ok {
+		if gerr.Code == http.StatusServiceUnavailable {
+			return true, "retryable error"
+		}
+	}
+	return false, ""
+}
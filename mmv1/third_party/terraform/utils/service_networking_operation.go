@@ -6,18 +6,13 @@ import (
 	"google.golang.org/api/servicenetworking/v1"
 )
 
-type ServiceNetworkingOperationWaiter struct {
-	Service *servicenetworking.APIService
-	CommonOperationWaiter
-}
-
-func (w *ServiceNetworkingOperationWaiter) QueryOp() (interface{}, error) {
-	return w.Service.Operations.Get(w.Op.Name).Do()
-}
-
 func serviceNetworkingOperationWaitTime(config *Config, op *servicenetworking.Operation, activity, userAgent string, timeout time.Duration) error {
-	w := &ServiceNetworkingOperationWaiter{
-		Service: config.NewServiceNetworkingClient(userAgent),
+	service := config.NewServiceNetworkingClient(userAgent)
+	w := &ShapedOperationWaiter{
+		Shape: LongRunningOperationShape,
+		QueryF: func(name string) (interface{}, error) {
+			return service.Operations.Get(name).Do()
+		},
 	}
 
 	if err := w.SetOp(op); err != nil {
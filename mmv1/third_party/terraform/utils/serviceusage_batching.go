@@ -1,6 +1,7 @@
 package google
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -52,10 +53,10 @@ func tryEnableRenamedService(service, altName string, project string, d *schema.
 	// use a short timeout- failures are likely
 
 	log.Printf("[DEBUG] attempting enabling service with user-specified name %s", service)
-	err = enableServiceUsageProjectServices([]string{service}, project, userAgent, config, 1*time.Minute)
+	err = enableServiceUsageProjectServices(context.Background(), []string{service}, project, userAgent, config, 1*time.Minute)
 	if err != nil {
 		log.Printf("[DEBUG] saw error %s. attempting alternate name %v", err, altName)
-		err2 := enableServiceUsageProjectServices([]string{altName}, project, userAgent, config, 1*time.Minute)
+		err2 := enableServiceUsageProjectServices(context.Background(), []string{altName}, project, userAgent, config, 1*time.Minute)
 		if err2 != nil {
 			return fmt.Errorf("Saw 2 subsequent errors attempting to enable a renamed service: %s / %s", err, err2)
 		}
@@ -98,17 +99,17 @@ func combineServiceUsageServicesBatches(srvsRaw interface{}, toAddRaw interface{
 }
 
 func sendBatchFuncEnableServices(config *Config, userAgent string, timeout time.Duration) BatcherSendFunc {
-	return func(project string, toEnableRaw interface{}) (interface{}, error) {
+	return func(ctx context.Context, project string, toEnableRaw interface{}) (interface{}, error) {
 		toEnable, ok := toEnableRaw.([]string)
 		if !ok {
 			return nil, fmt.Errorf("Expected batch body type to be []string, got %v. This is a provider error.", toEnableRaw)
 		}
-		return nil, enableServiceUsageProjectServices(toEnable, project, userAgent, config, timeout)
+		return nil, enableServiceUsageProjectServices(ctx, toEnable, project, userAgent, config, timeout)
 	}
 }
 
 func sendListServices(config *Config, userAgent string, timeout time.Duration) BatcherSendFunc {
-	return func(project string, _ interface{}) (interface{}, error) {
-		return listCurrentlyEnabledServices(project, userAgent, config, timeout)
+	return func(ctx context.Context, project string, _ interface{}) (interface{}, error) {
+		return listCurrentlyEnabledServices(ctx, project, userAgent, config, timeout)
 	}
 }
@@ -2,117 +2,41 @@ package google
 
 import (
 	"bytes"
-	"fmt"
-	"log"
 	"time"
 
 	sqladmin "google.golang.org/api/sqladmin/v1beta4"
 )
 
-type SqlAdminOperationWaiter struct {
-	Service *sqladmin.Service
-	Op      *sqladmin.Operation
-	Project string
-}
-
-func (w *SqlAdminOperationWaiter) State() string {
-	if w == nil {
-		return "Operation Waiter is nil!"
-	}
-
-	if w.Op == nil {
-		return "Operation is nil!"
-	}
-
-	return w.Op.Status
-}
-
-func (w *SqlAdminOperationWaiter) Error() error {
-	if w != nil && w.Op != nil && w.Op.Error != nil {
-		return SqlAdminOperationError(*w.Op.Error)
-	}
-	return nil
-}
-
-func (w *SqlAdminOperationWaiter) IsRetryable(error) bool {
-	return false
-}
-
-func (w *SqlAdminOperationWaiter) SetOp(op interface{}) error {
-	if op == nil {
-		// Starting as a log statement, this may be a useful error in the future
-		log.Printf("[DEBUG] attempted to set nil op")
-	}
-
-	sqlOp, ok := op.(*sqladmin.Operation)
-	w.Op = sqlOp
-	if !ok {
-		return fmt.Errorf("Unable to set operation. Bad type!")
-	}
-
-	return nil
-}
-
-func (w *SqlAdminOperationWaiter) QueryOp() (interface{}, error) {
-	if w == nil {
-		return nil, fmt.Errorf("Cannot query operation, waiter is unset or nil.")
-	}
-
-	if w.Op == nil {
-		return nil, fmt.Errorf("Cannot query operation, it's unset or nil.")
-	}
-
-	if w.Service == nil {
-		return nil, fmt.Errorf("Cannot query operation, service is nil.")
-	}
-
-	var op interface{}
-	var err error
-	err = retryTimeDuration(
-		func() error {
-			op, err = w.Service.Operations.Get(w.Project, w.Op.Name).Do()
+// SqlAdminOperationShape is the shape of Cloud SQL Admin operations.
+var SqlAdminOperationShape = &OperationShape{
+	NamePath:       "name",
+	StatusPath:     "status",
+	CompleteStatus: "DONE",
+	ErrorPath:      "error",
+	ErrorF: func(v interface{}) error {
+		var errs sqladmin.OperationErrors
+		if err := Convert(v, &errs); err != nil {
 			return err
-		},
-
-		DefaultRequestTimeout,
-	)
-
-	return op, err
-}
-
-func (w *SqlAdminOperationWaiter) OpName() string {
-	if w == nil {
-		return "<nil waiter>"
-	}
-
-	if w.Op == nil {
-		return "<nil op>"
-	}
-
-	return w.Op.Name
-}
-
-func (w *SqlAdminOperationWaiter) PendingStates() []string {
-	return []string{"PENDING", "RUNNING"}
-}
-
-func (w *SqlAdminOperationWaiter) TargetStates() []string {
-	return []string{"DONE"}
+		}
+		return SqlAdminOperationError(errs)
+	},
+	ProgressPaths: []string{"operationType"},
 }
 
 func sqlAdminOperationWaitTime(config *Config, res interface{}, project, activity, userAgent string, timeout time.Duration) error {
-	op := &sqladmin.Operation{}
-	err := Convert(res, op)
-	if err != nil {
-		return err
-	}
-
-	w := &SqlAdminOperationWaiter{
-		Service: config.NewSqlAdminClient(userAgent),
-		Op:      op,
-		Project: project,
+	service := config.NewSqlAdminClient(userAgent)
+	w := &ShapedOperationWaiter{
+		Shape: SqlAdminOperationShape,
+		QueryF: func(name string) (interface{}, error) {
+			var op interface{}
+			err := retryTimeDuration(func() (opErr error) {
+				op, opErr = service.Operations.Get(project, name).Do()
+				return opErr
+			}, DefaultRequestTimeout)
+			return op, err
+		},
 	}
-	if err := w.SetOp(op); err != nil {
+	if err := w.SetOp(res); err != nil {
 		return err
 	}
 	return OperationWait(w, activity, timeout, config.PollInterval)
@@ -0,0 +1,230 @@
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The environment variable naming the file HTTP trace records are appended
+// to. Tracing is disabled if it is unset.
+const httpTraceFileEnvVar = "GOOGLE_HTTP_TRACE_FILE"
+
+const traceRedacted = "REDACTED"
+
+// traceRecord is written as a single line of JSON for every HTTP attempt.
+type traceRecord struct {
+	Time           time.Time         `json:"time"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Status         int               `json:"status,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	LatencyMs      float64           `json:"latency_ms"`
+	Attempt        int               `json:"attempt,omitempty"`
+	BatchKey       string            `json:"batch_key,omitempty"`
+	DebugId        string            `json:"debug_id,omitempty"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+}
+
+type traceAttemptContextKey struct{}
+
+type traceInfoContextKey struct{}
+
+type traceRequestContextKey struct{}
+
+// traceInfo describes the batch and resource a request is made for.
+type traceInfo struct {
+	batchKey string
+	debugId  string
+}
+
+// contextWithTraceAttempt returns a context that marks requests made with it
+// as the given attempt, starting at 1.
+func contextWithTraceAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, traceAttemptContextKey{}, attempt)
+}
+
+// contextWithTraceInfo returns a context that marks requests made with it as
+// sent for the batch batchKey, on behalf of debugId.
+func contextWithTraceInfo(ctx context.Context, batchKey, debugId string) context.Context {
+	return context.WithValue(ctx, traceInfoContextKey{}, traceInfo{batchKey: batchKey, debugId: debugId})
+}
+
+// traceRequest holds the debug id of a request that has none of its own, so
+// every attempt at the request is recorded with the same id.
+type traceRequest struct {
+	once    sync.Once
+	debugId string
+}
+
+// contextWithTraceRequest returns a context that makes all requests made with
+// it share a debug id, which is assigned by the trace transport.
+func contextWithTraceRequest(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(traceRequestContextKey{}).(*traceRequest); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, traceRequestContextKey{}, &traceRequest{})
+}
+
+// traceRequestCount numbers the requests debug ids are assigned to.
+var traceRequestCount int64
+
+func newTraceDebugId() string {
+	return fmt.Sprintf("request %d", atomic.AddInt64(&traceRequestCount, 1))
+}
+
+// traceWriter writes trace records to w, one per line.
+type traceWriter struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func (tw *traceWriter) write(record *traceRecord) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(record); err != nil {
+		log.Printf("[WARN] Trace Transport: unable to encode trace record: %v", err)
+		return
+	}
+
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	// Write each record at once so records from concurrent requests, or from
+	// other provider instances appending to the same file, don't interleave.
+	if _, err := tw.w.Write(buf.Bytes()); err != nil {
+		log.Printf("[WARN] Trace Transport: unable to write trace record: %v", err)
+	}
+}
+
+type traceTransport struct {
+	writer   *traceWriter
+	internal http.RoundTripper
+}
+
+// traceFiles holds the writer of every trace file opened by this process.
+// Every Config shares the writer, so the file is only opened once.
+var traceFiles = struct {
+	mutex   sync.Mutex
+	writers map[string]*traceWriter
+}{writers: make(map[string]*traceWriter)}
+
+// newTransportWithTraceFile wraps t with a transport that appends a trace
+// record for every request to the file at path. If path is empty, t is
+// returned as-is.
+func newTransportWithTraceFile(t http.RoundTripper, path string) (http.RoundTripper, error) {
+	if path == "" {
+		return t, nil
+	}
+
+	traceFiles.mutex.Lock()
+	defer traceFiles.mutex.Unlock()
+	writer, ok := traceFiles.writers[path]
+	if !ok {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening HTTP trace file %q: %v", path, err)
+		}
+		writer = &traceWriter{w: f}
+		traceFiles.writers[path] = writer
+	}
+	return &traceTransport{writer: writer, internal: t}, nil
+}
+
+func newTransportWithTrace(t http.RoundTripper, w io.Writer) *traceTransport {
+	return &traceTransport{
+		writer:   &traceWriter{w: w},
+		internal: t,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface method.
+// It writes a trace record describing the request once it completes.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.internal.RoundTrip(req)
+
+	record := &traceRecord{
+		Time:           start.UTC(),
+		Method:         req.Method,
+		URL:            redactTraceURL(req.URL),
+		LatencyMs:      float64(time.Since(start)) / float64(time.Millisecond),
+		RequestHeaders: redactTraceHeaders(req.Header),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Status = resp.StatusCode
+	}
+
+	ctx := req.Context()
+	if attempt, ok := ctx.Value(traceAttemptContextKey{}).(int); ok {
+		record.Attempt = attempt
+	}
+	if info, ok := ctx.Value(traceInfoContextKey{}).(traceInfo); ok {
+		record.BatchKey = info.batchKey
+		record.DebugId = info.debugId
+	}
+	if record.DebugId == "" {
+		// Requests that aren't batched have no debug id of their own.
+		if tr, ok := ctx.Value(traceRequestContextKey{}).(*traceRequest); ok {
+			tr.once.Do(func() {
+				tr.debugId = newTraceDebugId()
+			})
+			record.DebugId = tr.debugId
+		} else {
+			record.DebugId = newTraceDebugId()
+		}
+	}
+
+	t.writer.write(record)
+	return resp, err
+}
+
+// isTraceSecret reports whether a header or query parameter name may hold a
+// credential.
+func isTraceSecret(name string) bool {
+	name = strings.ToLower(name)
+	return name == "authorization" || name == "key" || strings.Contains(name, "token") || strings.Contains(name, "api-key")
+}
+
+func redactTraceURL(u *url.URL) string {
+	query := u.Query()
+	redacted := false
+	for k := range query {
+		if isTraceSecret(k) {
+			query.Set(k, traceRedacted)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+
+	copied := *u
+	copied.RawQuery = query.Encode()
+	return copied.String()
+}
+
+func redactTraceHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(header))
+	for k, v := range header {
+		if isTraceSecret(k) {
+			headers[k] = traceRedacted
+			continue
+		}
+		headers[k] = strings.Join(v, ", ")
+	}
+	return headers
+}
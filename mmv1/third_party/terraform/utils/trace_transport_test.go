@@ -0,0 +1,209 @@
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func readTraceRecords(t *testing.T, buf *bytes.Buffer) []traceRecord {
+	var records []traceRecord
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var record traceRecord
+		if err := dec.Decode(&record); err != nil {
+			t.Fatalf("unable to decode trace record: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestTraceTransport_RecordsRetriedAttempts(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	client := ts.Client()
+	client.Transport = NewTransportWithDefaultRetries(newTransportWithTrace(http.DefaultTransport, &buf), &retryConfig{
+		timeout:        time.Minute,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     10 * time.Millisecond,
+	})
+
+	ctx := contextWithTraceInfo(context.Background(), "project/my-project/services", "Enable Project Service \"foo.googleapis.com\"")
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL+"/v1/projects/my-project", nil)
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	records := readTraceRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 trace records, got %d: %+v", len(records), records)
+	}
+	for i, record := range records {
+		expectedStatus := http.StatusServiceUnavailable
+		if i == 2 {
+			expectedStatus = http.StatusOK
+		}
+		if record.Attempt != i+1 {
+			t.Errorf("expected record %d to have attempt %d, got %d", i, i+1, record.Attempt)
+		}
+		if record.Status != expectedStatus {
+			t.Errorf("expected record %d to have status %d, got %d", i, expectedStatus, record.Status)
+		}
+		if record.Method != "GET" || record.URL != ts.URL+"/v1/projects/my-project" {
+			t.Errorf("expected record %d for GET %s/v1/projects/my-project, got %s %s", i, ts.URL, record.Method, record.URL)
+		}
+		if record.BatchKey != "project/my-project/services" || record.DebugId != "Enable Project Service \"foo.googleapis.com\"" {
+			t.Errorf("expected record %d to have the batch key and debug id of the request context, got %q and %q", i, record.BatchKey, record.DebugId)
+		}
+	}
+}
+
+func TestTraceTransport_AssignsDebugIds(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	client := ts.Client()
+	client.Transport = NewTransportWithDefaultRetries(newTransportWithTrace(http.DefaultTransport, &buf), &retryConfig{
+		timeout:        time.Minute,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     10 * time.Millisecond,
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	records := readTraceRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 trace records, got %d: %+v", len(records), records)
+	}
+	if records[0].DebugId == "" {
+		t.Fatalf("expected record to have a debug id, got %+v", records[0])
+	}
+	if records[1].DebugId != records[0].DebugId {
+		t.Errorf("expected attempts at a request to share debug id %q, got %q", records[0].DebugId, records[1].DebugId)
+	}
+	if records[2].DebugId == "" || records[2].DebugId == records[0].DebugId {
+		t.Errorf("expected requests to have different debug ids, got %q for both", records[2].DebugId)
+	}
+}
+
+func TestTraceTransport_SharesTraceFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "trace.json")
+	first, err := newTransportWithTraceFile(http.DefaultTransport, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := newTransportWithTraceFile(http.DefaultTransport, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.(*traceTransport).writer != second.(*traceTransport).writer {
+		t.Errorf("expected transports tracing to the same file to share its writer")
+	}
+}
+
+func TestTraceTransport_RedactsCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	client := ts.Client()
+	client.Transport = newTransportWithTrace(http.DefaultTransport, &buf)
+
+	req, err := http.NewRequest("GET", ts.URL+"/v1/projects/my-project?alt=json&access_token=secret-token&key=secret-key", nil)
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("X-Goog-Api-Key", "secret-key")
+	req.Header.Set("User-Agent", "Terraform")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("expected credentials to be redacted from trace, got %s", buf.String())
+	}
+
+	records := readTraceRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 trace record, got %d: %+v", len(records), records)
+	}
+	record := records[0]
+	if record.RequestHeaders["Authorization"] != traceRedacted {
+		t.Errorf("expected Authorization header to be redacted, got %q", record.RequestHeaders["Authorization"])
+	}
+	if record.RequestHeaders["User-Agent"] != "Terraform" {
+		t.Errorf("expected User-Agent header to be recorded, got %q", record.RequestHeaders["User-Agent"])
+	}
+	if !strings.Contains(record.URL, "alt=json") {
+		t.Errorf("expected non-credential query parameters to be recorded, got %q", record.URL)
+	}
+}
+
+func TestTraceTransport_RecordsErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	var buf bytes.Buffer
+	client := &http.Client{Transport: newTransportWithTrace(http.DefaultTransport, &buf)}
+	if _, err := client.Get(url); err == nil {
+		t.Fatalf("expected error requesting a closed server")
+	}
+
+	records := readTraceRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 trace record, got %d: %+v", len(records), records)
+	}
+	if records[0].Error == "" || records[0].Status != 0 {
+		t.Errorf("expected record with an error and no status, got %+v", records[0])
+	}
+}
@@ -0,0 +1,308 @@
+package google
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dnaeon/go-vcr/cassette"
+)
+
+// The environment variable that enables strict replaying when set to "true".
+// Requests that don't match any interaction are reported with the closest
+// recorded one, and tests fail if interactions of their cassette aren't all
+// replayed.
+const vcrStrictEnvVar = "VCR_STRICT"
+
+func isVcrStrict() bool {
+	return os.Getenv(vcrStrictEnvVar) == "true"
+}
+
+// vcrReplayLog records which interactions of a cassette have been replayed.
+type vcrReplayLog struct {
+	mutex    sync.Mutex
+	requests []cassette.Request
+	replayed []bool
+}
+
+// newVcrReplayLog loads the cassette at path, without its .yaml extension.
+func newVcrReplayLog(path string) (*vcrReplayLog, error) {
+	c, err := cassette.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	l := &vcrReplayLog{
+		replayed: make([]bool, len(c.Interactions)),
+	}
+	for _, i := range c.Interactions {
+		l.requests = append(l.requests, i.Request)
+	}
+	return l, nil
+}
+
+// matcher wraps matcher to record the interactions it matches. The recorder
+// replays the first interaction that matches and hasn't been replayed yet, so
+// the first recorded one equal to it is marked as replayed.
+func (l *vcrReplayLog) matcher(matcher cassette.Matcher) cassette.Matcher {
+	return func(r *http.Request, i cassette.Request) bool {
+		if !matcher(r, i) {
+			return false
+		}
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		for n, recorded := range l.requests {
+			if !l.replayed[n] && recorded.Method == i.Method && recorded.URL == i.URL && recorded.Body == i.Body {
+				l.replayed[n] = true
+				break
+			}
+		}
+		return true
+	}
+}
+
+// unused returns the interactions that have not been replayed.
+func (l *vcrReplayLog) unused() []cassette.Request {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var unused []cassette.Request
+	for n, r := range l.requests {
+		if !l.replayed[n] {
+			unused = append(unused, r)
+		}
+	}
+	return unused
+}
+
+// vcrBodyDiff is a difference between a recorded and a requested JSON body.
+// Recorded or Requested is unset if the field is missing from that body.
+type vcrBodyDiff struct {
+	Path      string      `json:"path"`
+	Recorded  interface{} `json:"recorded,omitempty"`
+	Requested interface{} `json:"requested,omitempty"`
+}
+
+// mismatchError describes the recorded interaction closest to a request that
+// matches none, and how their bodies differ. method, url and body are those of
+// the request, sanitized like recorded interactions are.
+func (l *vcrReplayLog) mismatchError(method, url, body string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	closest := -1
+	var closestDiffs []vcrBodyDiff
+	for n, r := range l.requests {
+		diffs := diffVcrBodies(r.Body, body)
+		if closest < 0 || compareVcrCandidates(method, url, r, diffs, l.replayed[n], l.requests[closest], closestDiffs, l.replayed[closest]) {
+			closest = n
+			closestDiffs = diffs
+		}
+	}
+	if closest < 0 {
+		return fmt.Errorf("%v for %s %s: the cassette has no interactions", cassette.ErrInteractionNotFound, method, url)
+	}
+
+	r := l.requests[closest]
+	msg := fmt.Sprintf("%v for %s %s. The closest recorded interaction, #%d, is %s %s", cassette.ErrInteractionNotFound, method, url, closest, r.Method, r.URL)
+	if l.replayed[closest] {
+		msg += ", which has already been replayed"
+	}
+	if len(closestDiffs) == 0 {
+		return fmt.Errorf("%s, with the same body.", msg)
+	}
+	diffs, err := json.MarshalIndent(closestDiffs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s, with a different body.", msg)
+	}
+	return fmt.Errorf("%s, with body differences:\n%s", msg, diffs)
+}
+
+// compareVcrCandidates reports whether recorded request a is closer to the
+// request with method and url than b is. Requests with the same method, then
+// the longest common URL prefix, then the fewest body differences, then those
+// that haven't been replayed yet are closest.
+func compareVcrCandidates(method, url string, a cassette.Request, aDiffs []vcrBodyDiff, aReplayed bool, b cassette.Request, bDiffs []vcrBodyDiff, bReplayed bool) bool {
+	if aMethod, bMethod := a.Method == method, b.Method == method; aMethod != bMethod {
+		return aMethod
+	}
+	if aPrefix, bPrefix := commonPrefixLength(a.URL, url), commonPrefixLength(b.URL, url); aPrefix != bPrefix {
+		return aPrefix > bPrefix
+	}
+	if len(aDiffs) != len(bDiffs) {
+		return len(aDiffs) < len(bDiffs)
+	}
+	return !aReplayed && bReplayed
+}
+
+func commonPrefixLength(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// diffVcrBodies returns the differences between a recorded and a requested
+// body. Bodies that aren't both JSON are compared as a whole.
+func diffVcrBodies(recorded, requested string) []vcrBodyDiff {
+	if recorded == requested {
+		return nil
+	}
+	var recordedJson, requestedJson interface{}
+	if json.Unmarshal([]byte(recorded), &recordedJson) != nil || json.Unmarshal([]byte(requested), &requestedJson) != nil {
+		return []vcrBodyDiff{{Path: "$", Recorded: recorded, Requested: requested}}
+	}
+	return diffVcrJson("$", recordedJson, requestedJson, nil)
+}
+
+func diffVcrJson(path string, recorded, requested interface{}, diffs []vcrBodyDiff) []vcrBodyDiff {
+	switch r := recorded.(type) {
+	case map[string]interface{}:
+		q, ok := requested.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]struct{})
+		for k := range r {
+			keys[k] = struct{}{}
+		}
+		for k := range q {
+			keys[k] = struct{}{}
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffs = diffVcrJson(path+"."+k, r[k], q[k], diffs)
+		}
+		return diffs
+	case []interface{}:
+		q, ok := requested.([]interface{})
+		if !ok {
+			break
+		}
+		for n := 0; n < len(r) || n < len(q); n++ {
+			var rItem, qItem interface{}
+			if n < len(r) {
+				rItem = r[n]
+			}
+			if n < len(q) {
+				qItem = q[n]
+			}
+			diffs = diffVcrJson(fmt.Sprintf("%s[%d]", path, n), rItem, qItem, diffs)
+		}
+		return diffs
+	}
+	if reflect.DeepEqual(recorded, requested) {
+		return diffs
+	}
+	return append(diffs, vcrBodyDiff{Path: path, Recorded: recorded, Requested: requested})
+}
+
+// reportMismatch replaces an interaction not found error with one describing
+// the closest recorded interaction to req.
+func (t *vcrTransport) reportMismatch(req *http.Request) error {
+	var body string
+	if req.Body != nil {
+		// The matcher replaces the body with a copy of it when it reads it.
+		b, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return fmt.Errorf("%v for %s %s: unable to read request body: %v", cassette.ErrInteractionNotFound, req.Method, req.URL, err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+		body = t.sanitizer.sanitizeBody(string(b))
+	}
+	return t.replays.mismatchError(req.Method, t.sanitizer.sanitizeURL(req.URL.String()), body)
+}
+
+// reportUnusedInteractions fails the test if interactions of its cassette were
+// never replayed.
+func (t *vcrTransport) reportUnusedInteractions(test *testing.T) {
+	if t.replays == nil {
+		return
+	}
+	unused := t.replays.unused()
+	if len(unused) == 0 {
+		return
+	}
+	var requests []string
+	for _, r := range unused {
+		requests = append(requests, fmt.Sprintf("  %s %s", r.Method, r.URL))
+	}
+	test.Errorf("%d recorded interactions were never replayed:\n%s", len(unused), strings.Join(requests, "\n"))
+}
+
+func TestVcrReplayLog_mismatchError(t *testing.T) {
+	l := &vcrReplayLog{
+		requests: []cassette.Request{
+			{Method: "GET", URL: "https://compute.googleapis.com/compute/v1/projects/p/global/networks/net-abc?alt=json"},
+			{Method: "POST", URL: "https://compute.googleapis.com/compute/v1/projects/p/global/networks?alt=json", Body: `{"name":"net-abc","autoCreateSubnetworks":false,"labels":{"a":"b"}}`},
+			{Method: "POST", URL: "https://compute.googleapis.com/compute/v1/projects/p/global/firewalls?alt=json", Body: `{"name":"fw"}`},
+		},
+		replayed: make([]bool, 3),
+	}
+
+	err := l.mismatchError("POST", "https://compute.googleapis.com/compute/v1/projects/p/global/networks?alt=json", `{"name":"net-abc","autoCreateSubnetworks":true,"labels":{"c":"d"}}`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	expected := `Requested interaction not found for POST https://compute.googleapis.com/compute/v1/projects/p/global/networks?alt=json. The closest recorded interaction, #1, is POST https://compute.googleapis.com/compute/v1/projects/p/global/networks?alt=json, with body differences:
+[
+  {
+    "path": "$.autoCreateSubnetworks",
+    "recorded": false,
+    "requested": true
+  },
+  {
+    "path": "$.labels.a",
+    "recorded": "b"
+  },
+  {
+    "path": "$.labels.c",
+    "requested": "d"
+  }
+]`
+	if err.Error() != expected {
+		t.Errorf("expected error\n%s\ngot\n%s", expected, err)
+	}
+
+	l.replayed[0] = true
+	err = l.mismatchError("GET", "https://compute.googleapis.com/compute/v1/projects/p/global/networks/net-abc?alt=json", "")
+	if err == nil || !strings.Contains(err.Error(), "#0") || !strings.Contains(err.Error(), "already been replayed") {
+		t.Errorf("expected error for an interaction that was already replayed, got %v", err)
+	}
+}
+
+func TestVcrReplayLog_unused(t *testing.T) {
+	requests := []cassette.Request{
+		{Method: "GET", URL: "https://example.com/a"},
+		{Method: "GET", URL: "https://example.com/a"},
+		{Method: "GET", URL: "https://example.com/b"},
+	}
+	l := &vcrReplayLog{requests: requests, replayed: make([]bool, len(requests))}
+	matcher := l.matcher(func(r *http.Request, i cassette.Request) bool {
+		return r.Method == i.Method && r.URL.String() == i.URL
+	})
+
+	req, err := http.NewRequest("GET", "https://example.com/a", nil)
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	if !matcher(req, requests[0]) {
+		t.Fatalf("expected request to match")
+	}
+
+	expected := []cassette.Request{requests[1], requests[2]}
+	if got := l.unused(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected unused interactions %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,557 @@
+package google
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+)
+
+// Placeholders saved to cassettes in place of the test project, so cassettes
+// can be shared and replayed against another project.
+const (
+	vcrProjectIdPlaceholder     = "vcr-placeholder-project"
+	vcrProjectNumberPlaceholder = "000000000000"
+)
+
+// The environment variable listing paths of JSON fields to redact from
+// cassettes in addition to vcrRedactedFields, separated by commas.
+const vcrRedactFieldsEnvVar = "VCR_REDACT_FIELDS"
+
+// vcrRedactedFields are the paths of JSON fields in request and response
+// bodies that hold secrets. Paths are dot separated field names from the root
+// of the body, and lists along the way are traversed.
+var vcrRedactedFields = []string{
+	// google_service_account_key
+	"privateKeyData",
+	// google_service_account_access_token and google_service_account_id_token
+	"accessToken",
+	"token",
+	// google_sql_user
+	"password",
+	// google_container_cluster
+	"masterAuth.password",
+	"masterAuth.clientKey",
+	// OAuth2 token responses
+	"access_token",
+	"refresh_token",
+	"id_token",
+}
+
+// vcrPlaintextPlaceholder is saved to cassettes in place of KMS plaintext.
+// Tests that check decrypted plaintext expect it when replaying, see
+// vcrPlaintext.
+const vcrPlaintextPlaceholder = "vcr-placeholder-plaintext"
+
+// vcrPlaceholderFields are the paths of JSON fields that hold secrets the
+// provider decodes, with the valid value saved to cassettes in their place.
+var vcrPlaceholderFields = map[string]string{
+	// google_kms_secret_ciphertext encrypts it, and google_kms_secret decrypts
+	// it from the response.
+	"plaintext": base64.StdEncoding.EncodeToString([]byte(vcrPlaintextPlaceholder)),
+}
+
+// vcrPlaintext returns the plaintext a test should expect KMS to decrypt,
+// which is the placeholder saved to cassettes when replaying.
+func vcrPlaintext(plaintext string) string {
+	if isVcrEnabled() && os.Getenv("VCR_MODE") == "REPLAYING" {
+		return vcrPlaintextPlaceholder
+	}
+	return plaintext
+}
+
+// vcrPlaceholder is a value that is saved to cassettes as placeholder.
+type vcrPlaceholder struct {
+	value       string
+	placeholder string
+	// isPart reports whether c can be part of the value, so the value is only
+	// replaced where it isn't part of a longer one.
+	isPart func(c byte) bool
+}
+
+// vcrField is the path of a JSON field and the value saved in its place.
+type vcrField struct {
+	path  []string
+	value string
+}
+
+// vcrSanitizer removes credentials, secrets and the test project from
+// cassettes before they're saved, and restores the test project in responses
+// replayed from them.
+type vcrSanitizer struct {
+	fields       []vcrField
+	placeholders []vcrPlaceholder
+}
+
+func newVcrSanitizer(project, projectNumber string, fields []string, placeholderFields map[string]string) *vcrSanitizer {
+	s := &vcrSanitizer{
+		fields: splitVcrFields(fields),
+	}
+	for f, v := range placeholderFields {
+		s.fields = append(s.fields, vcrField{path: strings.Split(f, "."), value: v})
+	}
+	if project != "" {
+		s.placeholders = append(s.placeholders, vcrPlaceholder{
+			value:       project,
+			placeholder: vcrProjectIdPlaceholder,
+			isPart: func(c byte) bool {
+				return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-'
+			},
+		})
+	}
+	if projectNumber != "" {
+		s.placeholders = append(s.placeholders, vcrPlaceholder{
+			value:       projectNumber,
+			placeholder: vcrProjectNumberPlaceholder,
+			isPart: func(c byte) bool {
+				return c >= '0' && c <= '9'
+			},
+		})
+	}
+	return s
+}
+
+// splitVcrFields splits dot separated field paths to be redacted, skipping
+// empty ones.
+func splitVcrFields(fields []string) []vcrField {
+	var paths []vcrField
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			paths = append(paths, vcrField{path: strings.Split(f, "."), value: traceRedacted})
+		}
+	}
+	return paths
+}
+
+// newVcrSanitizerFromEnv returns a sanitizer for the test project, that
+// redacts vcrRedactedFields and the fields listed in VCR_REDACT_FIELDS, and
+// replaces vcrPlaceholderFields.
+func newVcrSanitizerFromEnv() *vcrSanitizer {
+	fields := append([]string{}, vcrRedactedFields...)
+	if v := os.Getenv(vcrRedactFieldsEnvVar); v != "" {
+		fields = append(fields, strings.Split(v, ",")...)
+	}
+	return newVcrSanitizer(getTestProjectFromEnv(), multiEnvSearch(projectNumberEnvVars), fields, vcrPlaceholderFields)
+}
+
+// sanitizeInteraction is a cassette.Filter that sanitizes an interaction
+// before it's saved.
+func (s *vcrSanitizer) sanitizeInteraction(i *cassette.Interaction) error {
+	i.Request.URL = s.sanitizeURL(i.Request.URL)
+	i.Request.Headers = s.sanitizeHeaders(i.Request.Headers)
+	i.Request.Body = s.sanitizeBody(i.Request.Body)
+	i.Request.Form = s.sanitizeForm(i.Request.Form)
+	i.Response.Headers = s.sanitizeHeaders(i.Response.Headers)
+	i.Response.Body = s.sanitizeBody(i.Response.Body)
+	return nil
+}
+
+// sanitizeURL redacts credentials from the query of rawURL, and replaces the
+// test project with placeholders.
+func (s *vcrSanitizer) sanitizeURL(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		rawURL = redactTraceURL(u)
+	}
+	return s.replacePlaceholders(rawURL)
+}
+
+// sanitizeHeaders returns a copy of header with credentials redacted and the
+// test project replaced with placeholders. The headers of an interaction are
+// shared with the request and response it was recorded from, so they aren't
+// changed in place.
+func (s *vcrSanitizer) sanitizeHeaders(header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+	sanitized := make(http.Header, len(header))
+	for k, vs := range header {
+		if isTraceSecret(k) {
+			sanitized[k] = []string{traceRedacted}
+			continue
+		}
+		for _, v := range vs {
+			sanitized[k] = append(sanitized[k], s.replacePlaceholders(v))
+		}
+	}
+	return sanitized
+}
+
+func (s *vcrSanitizer) sanitizeForm(form url.Values) url.Values {
+	if form == nil {
+		return nil
+	}
+	sanitized := make(url.Values, len(form))
+	for k, vs := range form {
+		if isTraceSecret(k) {
+			sanitized[k] = []string{traceRedacted}
+			continue
+		}
+		for _, v := range vs {
+			sanitized[k] = append(sanitized[k], s.replacePlaceholders(v))
+		}
+	}
+	return sanitized
+}
+
+// sanitizeBody redacts the secret fields of a JSON body, and replaces the test
+// project with placeholders in any body. Bodies without secret fields are
+// otherwise left as they are.
+func (s *vcrSanitizer) sanitizeBody(body string) string {
+	if body == "" {
+		return body
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	// Keep numbers as they are, rather than converting them to float64.
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		redacted := false
+		for _, f := range s.fields {
+			redacted = redactVcrField(v, f.path, f.value) || redacted
+		}
+		if redacted {
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(v); err == nil {
+				body = strings.TrimSuffix(buf.String(), "\n")
+			}
+		}
+	}
+	return s.replacePlaceholders(body)
+}
+
+// redactVcrField replaces the field at path in v with value, and reports
+// whether there was one.
+func redactVcrField(v interface{}, path []string, value string) bool {
+	switch t := v.(type) {
+	case []interface{}:
+		redacted := false
+		for _, item := range t {
+			redacted = redactVcrField(item, path, value) || redacted
+		}
+		return redacted
+	case map[string]interface{}:
+		field, ok := t[path[0]]
+		if !ok || field == nil {
+			return false
+		}
+		if len(path) == 1 {
+			t[path[0]] = value
+			return true
+		}
+		return redactVcrField(field, path[1:], value)
+	}
+	return false
+}
+
+// replacePlaceholders replaces the test project in str with placeholders.
+func (s *vcrSanitizer) replacePlaceholders(str string) string {
+	for _, p := range s.placeholders {
+		str = replaceVcrValue(str, p.value, p.placeholder, p.isPart)
+	}
+	return str
+}
+
+// restorePlaceholders replaces placeholders in str with the test project.
+func (s *vcrSanitizer) restorePlaceholders(str string) string {
+	for _, p := range s.placeholders {
+		str = replaceVcrValue(str, p.placeholder, p.value, p.isPart)
+	}
+	return str
+}
+
+// replaceVcrValue replaces old with new in str, wherever old isn't directly
+// next to a byte that isPart of it.
+func replaceVcrValue(str, old, new string, isPart func(byte) bool) string {
+	var b strings.Builder
+	start := 0
+	for {
+		i := strings.Index(str[start:], old)
+		if i < 0 {
+			b.WriteString(str[start:])
+			return b.String()
+		}
+		i += start
+		end := i + len(old)
+		if (i > 0 && isPart(str[i-1])) || (end < len(str) && isPart(str[end])) {
+			b.WriteString(str[start:end])
+		} else {
+			b.WriteString(str[start:i])
+			b.WriteString(new)
+		}
+		start = end
+	}
+}
+
+// matchRequest matches requests to interactions by method, URL and body. Both
+// the request as sanitized by s and the raw request are tried, so interactions
+// recorded before cassettes were sanitized still match.
+func (s *vcrSanitizer) matchRequest(r *http.Request, i cassette.Request) bool {
+	if r.Method != i.Method {
+		return false
+	}
+	rawURL := r.URL.String()
+	sanitizedURL := s.sanitizeURL(rawURL)
+	if sanitizedURL != i.URL && rawURL != i.URL {
+		return false
+	}
+	if r.Body == nil {
+		return true
+	}
+	contentType := r.Header.Get("Content-Type")
+	// If body contains media, don't try to compare
+	if strings.Contains(contentType, "multipart/related") {
+		return true
+	}
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(r.Body); err != nil {
+		log.Printf("[DEBUG] Failed to read request body from cassette: %v", err)
+		return false
+	}
+	r.Body = ioutil.NopCloser(&b)
+	if sanitizedURL == i.URL && matchVcrBody(contentType, s.sanitizeBody(b.String()), i.Body) {
+		return true
+	}
+	return rawURL == i.URL && matchVcrBody(contentType, b.String(), i.Body)
+}
+
+// matchVcrBody reports whether the body of a request matches the body of an
+// interaction.
+func matchVcrBody(contentType, reqBody, cassetteBody string) bool {
+	// If body matches identically, we are done
+	if reqBody == cassetteBody {
+		return true
+	}
+
+	// JSON might be the same, but reordered. Try parsing json and comparing
+	if strings.Contains(contentType, "application/json") {
+		var reqJson, cassetteJson interface{}
+		if err := json.Unmarshal([]byte(reqBody), &reqJson); err != nil {
+			log.Printf("[DEBUG] Failed to unmarshall request json: %v", err)
+			return false
+		}
+		if err := json.Unmarshal([]byte(cassetteBody), &cassetteJson); err != nil {
+			log.Printf("[DEBUG] Failed to unmarshall cassette json: %v", err)
+			return false
+		}
+		return reflect.DeepEqual(reqJson, cassetteJson)
+	}
+	return false
+}
+
+// vcrTransport sends requests through a VCR recorder. When replaying, the test
+// project is restored in place of placeholders in responses.
+type vcrTransport struct {
+	recorder  *recorder.Recorder
+	sanitizer *vcrSanitizer
+	replaying bool
+	// replays is set when replaying strictly.
+	replays *vcrReplayLog
+}
+
+// RoundTrip implements the RoundTripper interface method.
+func (t *vcrTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.recorder.RoundTrip(req)
+	if err == cassette.ErrInteractionNotFound && t.replays != nil {
+		return nil, t.reportMismatch(req)
+	}
+	if err != nil || !t.replaying {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	restored := t.sanitizer.restorePlaceholders(string(body))
+	resp.Body = ioutil.NopCloser(strings.NewReader(restored))
+	if req.Method != "HEAD" {
+		resp.ContentLength = int64(len(restored))
+	}
+
+	// The headers are those of the interaction, so are copied before they're
+	// changed.
+	header := make(http.Header, len(resp.Header))
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			header[k] = append(header[k], t.sanitizer.restorePlaceholders(v))
+		}
+	}
+	resp.Header = header
+	return resp, nil
+}
+
+func TestVcrSanitizer_sanitizeInteraction(t *testing.T) {
+	s := newVcrSanitizer("my-project", "123456789", vcrRedactedFields, vcrPlaceholderFields)
+	i := &cassette.Interaction{
+		Request: cassette.Request{
+			Method: "POST",
+			URL:    "https://iam.googleapis.com/v1/projects/my-project/serviceAccounts/sa@my-project.iam.gserviceaccount.com/keys?alt=json&access_token=secret",
+			Headers: http.Header{
+				"Authorization": []string{"Bearer secret"},
+				"Content-Type":  []string{"application/json"},
+			},
+			Body: `{"keyAlgorithm":"KEY_ALG_RSA_2048"}`,
+		},
+		Response: cassette.Response{
+			Headers: http.Header{"Content-Type": []string{"application/json"}},
+			Body:    `{"name":"projects/my-project/serviceAccounts/sa@my-project.iam.gserviceaccount.com/keys/abc","privateKeyData":"secret","validAfterTime":"2021-01-01T00:00:00Z","nested":[{"projectNumber":"123456789","bigNumber":1234567890,"link":"projects/my-project-2"}]}`,
+		},
+	}
+	requestHeaders := i.Request.Headers
+
+	if err := s.sanitizeInteraction(i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedURL := "https://iam.googleapis.com/v1/projects/vcr-placeholder-project/serviceAccounts/sa@vcr-placeholder-project.iam.gserviceaccount.com/keys?access_token=REDACTED&alt=json"
+	if i.Request.URL != expectedURL {
+		t.Errorf("expected URL %q, got %q", expectedURL, i.Request.URL)
+	}
+	if got := i.Request.Headers.Get("Authorization"); got != traceRedacted {
+		t.Errorf("expected Authorization header to be redacted, got %q", got)
+	}
+	if got := requestHeaders.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("expected headers of the request not to be changed, got %q", got)
+	}
+	if i.Request.Body != `{"keyAlgorithm":"KEY_ALG_RSA_2048"}` {
+		t.Errorf("expected body without secrets to be unchanged, got %s", i.Request.Body)
+	}
+
+	expectedBody := `{"name":"projects/vcr-placeholder-project/serviceAccounts/sa@vcr-placeholder-project.iam.gserviceaccount.com/keys/abc","nested":[{"bigNumber":1234567890,"link":"projects/my-project-2","projectNumber":"000000000000"}],"privateKeyData":"REDACTED","validAfterTime":"2021-01-01T00:00:00Z"}`
+	if i.Response.Body != expectedBody {
+		t.Errorf("expected response body\n%s\ngot\n%s", expectedBody, i.Response.Body)
+	}
+	if strings.Contains(i.Response.Body, "secret") {
+		t.Errorf("expected secrets to be redacted, got %s", i.Response.Body)
+	}
+}
+
+func TestVcrSanitizer_redactNestedFields(t *testing.T) {
+	s := newVcrSanitizer("", "", []string{"masterAuth.password", "items.secret"}, nil)
+
+	cases := map[string]string{
+		`{"masterAuth":{"username":"admin","password":"hunter2"}}`: `{"masterAuth":{"password":"REDACTED","username":"admin"}}`,
+		`{"items":[{"secret":"a"},{"other":"b"}]}`:                 `{"items":[{"secret":"REDACTED"},{"other":"b"}]}`,
+		`{"password":"not at the path"}`:                           `{"password":"not at the path"}`,
+		`not json, password`:                                       `not json, password`,
+	}
+	for body, expected := range cases {
+		if got := s.sanitizeBody(body); got != expected {
+			t.Errorf("expected %s to be sanitized to %s, got %s", body, expected, got)
+		}
+	}
+}
+
+func TestVcrSanitizer_restorePlaceholders(t *testing.T) {
+	recorded := newVcrSanitizer("my-project", "123456789", nil, nil)
+	replayed := newVcrSanitizer("other-project", "987654321", nil, nil)
+
+	body := `{"name":"projects/my-project/zones/us-central1-a","number":"123456789","id":"1234567890"}`
+	sanitized := recorded.sanitizeBody(body)
+	expected := `{"name":"projects/other-project/zones/us-central1-a","number":"987654321","id":"1234567890"}`
+	if got := replayed.restorePlaceholders(sanitized); got != expected {
+		t.Errorf("expected %s to be restored to %s, got %s", sanitized, expected, got)
+	}
+
+	// Requests made when replaying match the sanitized interaction.
+	req, err := http.NewRequest("GET", "https://compute.googleapis.com/compute/v1/projects/other-project/global/networks?alt=json", nil)
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	i := cassette.Request{
+		Method: "GET",
+		URL:    recorded.sanitizeURL("https://compute.googleapis.com/compute/v1/projects/my-project/global/networks?alt=json"),
+	}
+	if !replayed.matchRequest(req, i) {
+		t.Errorf("expected request for %s to match interaction for %s", req.URL, i.URL)
+	}
+}
+
+func TestVcrSanitizer_replacePlaintext(t *testing.T) {
+	s := newVcrSanitizer("", "", vcrRedactedFields, vcrPlaceholderFields)
+	i := &cassette.Interaction{
+		Request: cassette.Request{
+			Method: "POST",
+			URL:    "https://cloudkms.googleapis.com/v1/projects/p/locations/global/keyRings/r/cryptoKeys/k:decrypt?alt=json",
+			Body:   `{"ciphertext":"Y2lwaGVydGV4dA=="}`,
+		},
+		Response: cassette.Response{
+			Body: `{"plaintext":"c2VjcmV0"}`,
+		},
+	}
+	if err := s.sanitizeInteraction(i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// google_kms_secret decodes the plaintext of responses, so a valid
+	// placeholder is saved in its place.
+	expected := `{"plaintext":"dmNyLXBsYWNlaG9sZGVyLXBsYWludGV4dA=="}`
+	if i.Response.Body != expected {
+		t.Errorf("expected response body %s, got %s", expected, i.Response.Body)
+	}
+
+	i = &cassette.Interaction{
+		Request: cassette.Request{
+			Method: "POST",
+			URL:    "https://cloudkms.googleapis.com/v1/projects/p/locations/global/keyRings/r/cryptoKeys/k:encrypt?alt=json",
+			Body:   `{"plaintext":"c2VjcmV0"}`,
+		},
+	}
+	if err := s.sanitizeInteraction(i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if i.Request.Body != expected {
+		t.Errorf("expected request body %s, got %s", expected, i.Request.Body)
+	}
+
+	req, err := http.NewRequest("POST", i.Request.URL, strings.NewReader(`{"plaintext":"c2VjcmV0"}`))
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	if !s.matchRequest(req, i.Request) {
+		t.Errorf("expected request to match the sanitized interaction")
+	}
+}
+
+func TestVcrSanitizer_matchUnsanitizedInteraction(t *testing.T) {
+	s := newVcrSanitizer("my-project", "123456789", vcrRedactedFields, vcrPlaceholderFields)
+
+	// Cassettes recorded before they were sanitized have the real project and
+	// secrets.
+	i := cassette.Request{
+		Method: "POST",
+		URL:    "https://sqladmin.googleapis.com/sql/v1beta4/projects/my-project/instances/i/users?alt=json",
+		Body:   `{"name":"user","password":"hunter2","project":"my-project"}`,
+	}
+	req, err := http.NewRequest("POST", i.URL, strings.NewReader(`{"project":"my-project","name":"user","password":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if !s.matchRequest(req, i) {
+		t.Errorf("expected request to match the unsanitized interaction")
+	}
+
+	req, err = http.NewRequest("POST", i.URL, strings.NewReader(`{"project":"my-project","name":"user","password":"other"}`))
+	if err != nil {
+		t.Fatalf("unable to construct request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.matchRequest(req, i) {
+		t.Errorf("expected request with another body not to match")
+	}
+}
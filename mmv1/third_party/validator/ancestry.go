@@ -0,0 +1,156 @@
+package google
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// AncestryLookup looks up the ancestry of existing projects and folders.
+type AncestryLookup interface {
+	// GetAncestry returns the ancestors of the project or folder named name,
+	// in "projects/<id>" or "folders/<id>" format. They start with the
+	// resource itself and end with its organization, if it has one.
+	GetAncestry(name string) ([]string, error)
+}
+
+// resourceManagerAncestryLookup is an AncestryLookup backed by Resource Manager.
+type resourceManagerAncestryLookup struct {
+	config *Config
+}
+
+// NewResourceManagerAncestryLookup returns an AncestryLookup that asks
+// Resource Manager about existing projects and folders.
+func NewResourceManagerAncestryLookup(config *Config) AncestryLookup {
+	return &resourceManagerAncestryLookup{config: config}
+}
+
+func (l *resourceManagerAncestryLookup) GetAncestry(name string) ([]string, error) {
+	if strings.HasPrefix(name, "projects/") {
+		resp, err := l.config.NewResourceManagerClient(l.config.userAgent).Projects.GetAncestry(strings.TrimPrefix(name, "projects/"), &cloudresourcemanager.GetAncestryRequest{}).Do()
+		if err != nil {
+			return nil, fmt.Errorf("getting ancestry of %s: %v", name, err)
+		}
+		var ancestors []string
+		for _, a := range resp.Ancestor {
+			ancestors = append(ancestors, resourceIdName(a.ResourceId))
+		}
+		return ancestors, nil
+	}
+
+	// Folders only know their parent, so walk up to the organization.
+	ancestors := []string{name}
+	for strings.HasPrefix(name, "folders/") {
+		folder, err := l.config.NewResourceManagerV2Client(l.config.userAgent).Folders.Get(name).Do()
+		if err != nil {
+			return nil, fmt.Errorf("getting parent of %s: %v", name, err)
+		}
+		if folder.Parent == "" {
+			break
+		}
+		name = folder.Parent
+		ancestors = append(ancestors, name)
+	}
+	return ancestors, nil
+}
+
+// resourceIdName returns the name of a Resource Manager resource id, such as
+// "folders/123" for a folder.
+func resourceIdName(id *cloudresourcemanager.ResourceId) string {
+	return fmt.Sprintf("%ss/%s", id.Type, id.Id)
+}
+
+// The scope of an asset, such as "projects/my-project", within its name.
+var assetScopeRe = regexp.MustCompile(`^//[^/]+/((?:projects|folders|organizations)/[^/]+)`)
+
+// AncestryResolver resolves the ancestors of assets. Projects created in the
+// same plan are resolved from their configuration, and existing projects and
+// folders from an AncestryLookup.
+type AncestryResolver struct {
+	lookup AncestryLookup
+	// The parents of projects created in the same plan, by project id.
+	plannedParents map[string]string
+	cache          map[string][]string
+}
+
+func NewAncestryResolver(lookup AncestryLookup) *AncestryResolver {
+	return &AncestryResolver{
+		lookup:         lookup,
+		plannedParents: make(map[string]string),
+		cache:          make(map[string][]string),
+	}
+}
+
+// AddPlannedProject records the parent of a google_project created in the same
+// plan, from its folder_id or org_id.
+func (r *AncestryResolver) AddPlannedProject(d TerraformResourceData) error {
+	p := &cloudresourcemanager.Project{}
+	if err := getParentResourceId(d, p); err != nil {
+		return err
+	}
+	if p.Parent == nil {
+		return nil
+	}
+	r.plannedParents[d.Get("project_id").(string)] = resourceIdName(p.Parent)
+	return nil
+}
+
+// SetAncestors populates the Ancestors of assets, converted from d.
+func (r *AncestryResolver) SetAncestors(d TerraformResourceData, config *Config, assets []Asset) error {
+	for i := range assets {
+		ancestors, err := r.AssetAncestors(d, config, assets[i].Name)
+		if err != nil {
+			return err
+		}
+		assets[i].Ancestors = ancestors
+	}
+	return nil
+}
+
+// AssetAncestors returns the ancestors of the asset named name, converted from
+// d, starting with the project, folder or organization the asset is in. Assets
+// whose names don't include it, such as storage buckets, are in the project of
+// d. Assets whose scope is unknown until they are created have no ancestors.
+func (r *AncestryResolver) AssetAncestors(d TerraformResourceData, config *Config, name string) ([]string, error) {
+	var scope string
+	if m := assetScopeRe.FindStringSubmatch(name); m != nil {
+		scope = m[1]
+	} else {
+		project, err := getProject(d, config)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the project of asset %q: %v", name, err)
+		}
+		scope = "projects/" + project
+	}
+	if strings.Contains(scope, "/placeholder-") {
+		return nil, nil
+	}
+	return r.ancestors(scope)
+}
+
+func (r *AncestryResolver) ancestors(name string) ([]string, error) {
+	if strings.HasPrefix(name, "organizations/") {
+		return []string{name}, nil
+	}
+	if parent, ok := r.plannedParents[strings.TrimPrefix(name, "projects/")]; ok {
+		ancestors, err := r.ancestors(parent)
+		if err != nil {
+			return nil, err
+		}
+		return append([]string{name}, ancestors...), nil
+	}
+	if ancestors, ok := r.cache[name]; ok {
+		return ancestors, nil
+	}
+	if r.lookup == nil {
+		return nil, fmt.Errorf("unable to resolve the ancestry of %s without a lookup", name)
+	}
+	ancestors, err := r.lookup.GetAncestry(name)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[name] = ancestors
+	return ancestors, nil
+}
@@ -0,0 +1,122 @@
+package google
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakeAncestryLookup is an in-memory AncestryLookup.
+type fakeAncestryLookup struct {
+	ancestry map[string][]string
+	calls    map[string]int
+}
+
+func (l *fakeAncestryLookup) GetAncestry(name string) ([]string, error) {
+	l.calls[name]++
+	ancestors, ok := l.ancestry[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	return ancestors, nil
+}
+
+func TestAncestryResolver(t *testing.T) {
+	lookup := &fakeAncestryLookup{
+		ancestry: map[string][]string{
+			"projects/existing": {"projects/existing", "folders/456", "organizations/789"},
+			"folders/123":       {"folders/123", "folders/456", "organizations/789"},
+		},
+		calls: make(map[string]int),
+	}
+	r := NewAncestryResolver(lookup)
+
+	planned := []*mockTerraformResourceData{
+		{m: map[string]interface{}{"project_id": "in-folder", "folder_id": "folders/123", "org_id": ""}},
+		{m: map[string]interface{}{"project_id": "in-org", "folder_id": "", "org_id": "789"}},
+		{m: map[string]interface{}{"project_id": "no-parent", "folder_id": "", "org_id": ""}},
+	}
+	for _, d := range planned {
+		if err := r.AddPlannedProject(d); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		asset    string
+		expected []string
+	}{
+		{
+			name:     "PlannedProjectInFolder",
+			asset:    "//compute.googleapis.com/projects/in-folder/zones/us-central1-a/instances/vm",
+			expected: []string{"projects/in-folder", "folders/123", "folders/456", "organizations/789"},
+		},
+		{
+			name:     "PlannedProjectInOrg",
+			asset:    "//cloudresourcemanager.googleapis.com/projects/in-org",
+			expected: []string{"projects/in-org", "organizations/789"},
+		},
+		{
+			name:     "ExistingProject",
+			asset:    "//compute.googleapis.com/projects/existing/global/networks/my-network",
+			expected: []string{"projects/existing", "folders/456", "organizations/789"},
+		},
+		{
+			name:     "Folder",
+			asset:    "//cloudresourcemanager.googleapis.com/folders/123",
+			expected: []string{"folders/123", "folders/456", "organizations/789"},
+		},
+		{
+			name:     "Organization",
+			asset:    "//cloudresourcemanager.googleapis.com/organizations/789",
+			expected: []string{"organizations/789"},
+		},
+		{
+			name:  "Placeholder",
+			asset: "//compute.googleapis.com/projects/placeholder-abcdefgh/zones/us-central1-a/instances/vm",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := r.AssetAncestors(&mockTerraformResourceData{}, nil, c.asset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, c.expected) {
+				t.Errorf("got %v, expected %v", got, c.expected)
+			}
+		})
+	}
+
+	if n := lookup.calls["folders/123"]; n != 1 {
+		t.Errorf("expected the ancestry of folders/123 to be looked up once, got %d", n)
+	}
+	if _, err := r.AssetAncestors(&mockTerraformResourceData{}, nil, "//cloudresourcemanager.googleapis.com/projects/no-parent"); err == nil {
+		t.Errorf("expected an error for a planned project without a known parent")
+	}
+}
+
+func TestAncestryResolver_SetAncestors(t *testing.T) {
+	r := NewAncestryResolver(&fakeAncestryLookup{
+		ancestry: map[string][]string{
+			"projects/my-project": {"projects/my-project", "organizations/789"},
+		},
+		calls: make(map[string]int),
+	})
+	// Bucket names don't include their project.
+	d := &mockTerraformResourceData{m: map[string]interface{}{"project": "my-project"}}
+	assets := []Asset{
+		{Name: "//storage.googleapis.com/my-bucket"},
+		{Name: "//compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/vm"},
+	}
+	if err := r.SetAncestors(d, nil, assets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"projects/my-project", "organizations/789"}
+	for _, a := range assets {
+		if !reflect.DeepEqual(a.Ancestors, expected) {
+			t.Errorf("%s: got ancestors %v, expected %v", a.Name, a.Ancestors, expected)
+		}
+	}
+}
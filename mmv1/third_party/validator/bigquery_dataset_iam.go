@@ -0,0 +1,52 @@
+package google
+
+func GetBigqueryDatasetIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newBigqueryDatasetIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetBigqueryDatasetIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newBigqueryDatasetIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetBigqueryDatasetIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newBigqueryDatasetIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeBigqueryDatasetIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeBigqueryDatasetIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeBigqueryDatasetIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeBigqueryDatasetIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeBigqueryDatasetIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newBigqueryDatasetIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	return newResourceIamAsset(d, config, expandBindings, "//bigquery.googleapis.com/projects/{{project}}/datasets/{{dataset_id}}", "bigquery.googleapis.com/Dataset")
+}
+
+func FetchBigqueryDatasetIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	return fetchIamPolicy(
+		NewBigqueryDatasetIamUpdater,
+		d,
+		config,
+		"//bigquery.googleapis.com/projects/{{project}}/datasets/{{dataset_id}}",
+		"bigquery.googleapis.com/Dataset",
+	)
+}
@@ -95,7 +95,7 @@ func expandBigQueryTableTableReference(v interface{}, d TerraformResourceData, c
 	if err != nil {
 		return nil, err
 	} else if val := reflect.ValueOf(transformedProjectId); val.IsValid() && !isEmptyValue(val) {
-		transformed["project"] = transformedProjectId
+		transformed["projectId"] = transformedProjectId
 	}
 
 	transformedDatasetId, err := expandBigQueryTableTableReferenceDatasetId(d.Get("dataset_id"), d, config)
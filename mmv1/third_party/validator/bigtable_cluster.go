@@ -58,11 +58,11 @@ func expandBigtableClusters(v interface{}, d TerraformResourceData, config *Conf
 			transformed["location"] = transformedLocation
 		}
 
-		transformedServerNodes, err := expandBigtableClusterServerNodes(original["num_nodes"], d, config)
+		transformedServeNodes, err := expandBigtableClusterServeNodes(original["num_nodes"], d, config)
 		if err != nil {
 			return nil, err
-		} else if val := reflect.ValueOf(transformedServerNodes); val.IsValid() && !isEmptyValue(val) {
-			transformed["serverNodes"] = transformedServerNodes
+		} else if val := reflect.ValueOf(transformedServeNodes); val.IsValid() && !isEmptyValue(val) {
+			transformed["serveNodes"] = transformedServeNodes
 		}
 
 		transformedStorageType, err := expandBigtableClusterDefaultStorageType(original["storage_type"], d, config)
@@ -88,7 +88,7 @@ func expandBigtableClusterLocation(v interface{}, d TerraformResourceData, confi
 	return v, nil
 }
 
-func expandBigtableClusterServerNodes(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+func expandBigtableClusterServeNodes(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
 	return v, nil
 }
 
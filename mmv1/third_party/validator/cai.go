@@ -1,9 +1,12 @@
 package google
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"math/rand"
 	"regexp"
+	"sort"
+	"strings"
 )
 
 // Asset is the CAI representation of a resource.
@@ -15,6 +18,9 @@ type Asset struct {
 	Resource  *AssetResource `json:"resource,omitempty"`
 	IAMPolicy *IAMPolicy     `json:"iam_policy,omitempty"`
 	OrgPolicy []*OrgPolicy   `json:"org_policy,omitempty"`
+	// The project, folders and organization the resource is in, from the
+	// closest, in `<type>s/<id>` format. Set by an AncestryResolver.
+	Ancestors []string `json:"ancestors,omitempty"`
 }
 
 // AssetResource is the Asset's Resource field.
@@ -31,15 +37,53 @@ type AssetResource struct {
 	// as there are occasional deviations between CAI and API responses.
 	// This returns the API response values instead.
 	Data map[string]interface{} `json:"data,omitempty"`
+	// Paths of the Data fields whose values are unknown until apply, such as
+	// "networkInterface.0.networkIp". Set by SetUnknownFields.
+	UnknownFields []string `json:"unknown_fields,omitempty"`
+}
+
+// TerraformAddressed is implemented by resource data that knows the address of
+// its resource in the Terraform configuration, such as
+// "google_compute_instance.vm".
+type TerraformAddressed interface {
+	TerraformAddress() string
+}
+
+// UnknownValueReporter is implemented by resource data that knows which of its
+// fields are unknown until apply, such as resource data read from a plan.
+type UnknownValueReporter interface {
+	// UnknownFields returns the paths of those fields, such as
+	// "network_interface.0.network_ip".
+	UnknownFields() []string
 }
 
 type IAMPolicy struct {
-	Bindings []IAMBinding `json:"bindings"`
+	Bindings     []IAMBinding     `json:"bindings"`
+	AuditConfigs []IAMAuditConfig `json:"audit_configs,omitempty"`
 }
 
 type IAMBinding struct {
-	Role    string   `json:"role"`
-	Members []string `json:"members"`
+	Role      string        `json:"role"`
+	Members   []string      `json:"members"`
+	Condition *IAMCondition `json:"condition,omitempty"`
+}
+
+// IAMCondition is the condition an IAMBinding is granted under.
+type IAMCondition struct {
+	Expression  string `json:"expression"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// IAMAuditConfig is the audit logging configuration of a single service.
+type IAMAuditConfig struct {
+	Service         string              `json:"service"`
+	AuditLogConfigs []IAMAuditLogConfig `json:"audit_log_configs"`
+}
+
+type IAMAuditLogConfig struct {
+	LogType         string   `json:"log_type"`
+	ExemptedMembers []string `json:"exempted_members,omitempty"`
 }
 
 type OrgPolicy struct {
@@ -74,7 +118,7 @@ type RestoreDefault struct {
 
 // assetName templates an asset.name by looking up and replacing all instances
 // of {{field}}. In the case where a field would resolve to an empty string, a
-// generated unique string will be used: "placeholder-" + randomString().
+// placeholder will be used: "placeholder-" + placeholderSuffix().
 // This is done to preserve uniqueness of asset.name for a given asset.asset_type.
 func assetName(d TerraformResourceData, config *Config, linkTmpl string) (string, error) {
 	re := regexp.MustCompile("{{([[:word:]]+)}}")
@@ -87,7 +131,7 @@ func assetName(d TerraformResourceData, config *Config, linkTmpl string) (string
 	fWithPlaceholder := func(key string) string {
 		val := f(key)
 		if val == "" {
-			val = fmt.Sprintf("placeholder-%s", randString(8))
+			val = fmt.Sprintf("placeholder-%s", placeholderSuffix(d, strings.Trim(key, "{}")))
 		}
 		return val
 	}
@@ -95,6 +139,57 @@ func assetName(d TerraformResourceData, config *Config, linkTmpl string) (string
 	return re.ReplaceAllStringFunc(linkTmpl, fWithPlaceholder), nil
 }
 
+// placeholderSuffix returns 8 characters unique to field of the resource of d.
+// They are derived from the address of the resource when d knows it, so that
+// converting the same plan twice gives the same asset names, and random
+// otherwise.
+func placeholderSuffix(d TerraformResourceData, field string) string {
+	if a, ok := d.(TerraformAddressed); ok && a.TerraformAddress() != "" {
+		sum := sha256.Sum256([]byte(a.TerraformAddress() + "/" + field))
+		return fmt.Sprintf("%x", sum[:4])
+	}
+	return randString(8)
+}
+
+// SetUnknownFields records the Data fields of assets whose values are unknown
+// until apply, if d knows them. Terraform field paths are converted to lower
+// camel case, which is how converters name most Data fields.
+func SetUnknownFields(d TerraformResourceData, assets []Asset) {
+	r, ok := d.(UnknownValueReporter)
+	if !ok {
+		return
+	}
+	var fields []string
+	for _, f := range r.UnknownFields() {
+		fields = append(fields, unknownDataField(f))
+	}
+	if len(fields) == 0 {
+		return
+	}
+	sort.Strings(fields)
+	for i := range assets {
+		if assets[i].Resource != nil {
+			assets[i].Resource.UnknownFields = fields
+		}
+	}
+}
+
+// unknownDataField converts a Terraform field path, such as
+// "network_interface.0.network_ip", to lower camel case.
+func unknownDataField(path string) string {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		words := strings.Split(p, "_")
+		for j := 1; j < len(words); j++ {
+			if words[j] != "" {
+				words[j] = strings.ToUpper(words[j][:1]) + words[j][1:]
+			}
+		}
+		parts[i] = strings.Join(words, "")
+	}
+	return strings.Join(parts, ".")
+}
+
 func randString(n int) string {
 	const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, n)
@@ -1,8 +1,19 @@
 package google
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAssetName(t *testing.T) {
@@ -51,6 +62,446 @@ func TestAssetName(t *testing.T) {
 	}
 }
 
+func TestAssetName_deterministicPlaceholder(t *testing.T) {
+	newData := func(address string) TerraformResourceData {
+		return &mockAddressedResourceData{
+			mockTerraformResourceData: mockTerraformResourceData{
+				m: map[string]interface{}{"a": "value-a"},
+			},
+			address: address,
+		}
+	}
+
+	first, err := assetName(newData("google_compute_instance.vm"), nil, "//{{a}}/{{b}}/{{c}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := assetName(newData("google_compute_instance.vm"), nil, "//{{a}}/{{b}}/{{c}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("expected the same name for the same address, got %v and %v", first, second)
+	}
+	if r := regexp.MustCompile(`^//value-a/placeholder-[0-9a-f]{8}/placeholder-[0-9a-f]{8}$`); !r.MatchString(first) {
+		t.Errorf("got %v, expected pattern %v", first, r)
+	}
+	if parts := strings.Split(first, "/"); parts[3] == parts[4] {
+		t.Errorf("expected different placeholders for different fields, got %v", first)
+	}
+
+	other, err := assetName(newData("google_compute_instance.other"), nil, "//{{a}}/{{b}}/{{c}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first {
+		t.Errorf("expected different names for different addresses, got %v", other)
+	}
+}
+
+func TestSetUnknownFields(t *testing.T) {
+	d := &mockAddressedResourceData{
+		unknownFields: []string{"network_interface.0.network_ip", "self_link"},
+	}
+	assets := []Asset{
+		{Name: "//compute.googleapis.com/projects/p/zones/z/instances/vm", Resource: &AssetResource{}},
+		{Name: "//compute.googleapis.com/projects/p/zones/z/instances/vm"},
+	}
+	SetUnknownFields(d, assets)
+
+	expected := []string{"networkInterface.0.networkIp", "selfLink"}
+	if got := assets[0].Resource.UnknownFields; !reflect.DeepEqual(got, expected) {
+		t.Errorf("got unknown fields %v, expected %v", got, expected)
+	}
+	if assets[1].Resource != nil {
+		t.Errorf("expected no resource to be added, got %v", assets[1].Resource)
+	}
+}
+
+// Fields that converters deliberately set outside of their discovery schemas,
+// by converter file.
+var knownDiscoverySchemaDeviations = map[string][]string{
+	// Buckets only know the number of their project, so policies on the
+	// project need its id.
+	"storage_bucket.go": {"project"},
+	// URL parameters of the API, kept for policies written against them.
+	"cloudfunctions_function.go": {"region"},
+	// Node pools keep the URL parameters of their cluster too, and pod
+	// security policies are only in the v1beta1 API.
+	"container.go": {"cluster", "location", "podSecurityPolicyConfig"},
+	// Int64 values are wrapped in objects, as in CAI.
+	"bigquery_table.go": {"value"},
+}
+
+// TestDiscoveryConformance checks converters against the discovery documents
+// in testdata/discovery, which are trimmed copies of the published documents
+// that keep the schemas converters use. Set DISCOVERY_DOCS_DIR to check them
+// against other documents, such as freshly downloaded ones.
+//
+// The discovery documents and schemas that converters name must exist, and
+// the fields they set must be fields of those schemas. The data of converters
+// that can run on mock resource data is checked in full, without the fields
+// known to deviate from their schemas.
+func TestDiscoveryConformance(t *testing.T) {
+	dir := os.Getenv("DISCOVERY_DOCS_DIR")
+	if dir == "" {
+		dir = filepath.Join("testdata", "discovery")
+	}
+	docs, err := LoadDiscoveryDocuments(dir)
+	if err != nil {
+		t.Fatalf("loading discovery documents: %v", err)
+	}
+
+	sources, err := readConverterSources(".")
+	if err != nil {
+		t.Fatalf("reading converters: %v", err)
+	}
+	for file, src := range sources {
+		known := make(map[string]struct{})
+		var schemaNames []string
+		resolved := true
+		for _, s := range src.schemas {
+			names, err := docs.fieldNames(s.uri, s.name)
+			if err != nil {
+				t.Errorf("%s: %v", file, err)
+				resolved = false
+				continue
+			}
+			for n := range names {
+				known[n] = struct{}{}
+			}
+			schemaNames = append(schemaNames, s.name)
+		}
+		if !resolved {
+			continue
+		}
+		for _, f := range knownDiscoverySchemaDeviations[file] {
+			known[f] = struct{}{}
+		}
+		for _, f := range src.fields {
+			if _, ok := known[f]; !ok {
+				t.Errorf("%s: sets %q, which isn't a field of %s", file, f, strings.Join(schemaNames, " or "))
+			}
+		}
+	}
+
+	project := map[string]interface{}{
+		"project_id":      "my-project",
+		"name":            "My Project",
+		"org_id":          "",
+		"folder_id":       "folders/123",
+		"labels":          map[string]interface{}{"env": "test"},
+		"billing_account": "000000-000000-000000",
+	}
+	nodeConfig := map[string]interface{}{
+		"machine_type":    "n1-standard-1",
+		"disk_size_gb":    100,
+		"disk_type":       "pd-ssd",
+		"oauth_scopes":    schema.NewSet(schema.HashString, []interface{}{"https://www.googleapis.com/auth/cloud-platform"}),
+		"service_account": "sa@my-project.iam.gserviceaccount.com",
+		"metadata":        map[string]interface{}{"disable-legacy-endpoints": "true"},
+		"image_type":      "COS",
+		"labels":          map[string]interface{}{"env": "test"},
+		"local_ssd_count": 1,
+		"tags":            []interface{}{"web"},
+		"preemptible":     true,
+		"guest_accelerator": []interface{}{
+			map[string]interface{}{"count": 1, "type": "nvidia-tesla-k80"},
+		},
+		"min_cpu_platform": "Intel Skylake",
+		"workload_metadata_config": []interface{}{
+			map[string]interface{}{"node_metadata": "GKE_METADATA_SERVER"},
+		},
+		"taint": []interface{}{
+			map[string]interface{}{"key": "dedicated", "value": "web", "effect": "NO_SCHEDULE"},
+		},
+	}
+	cases := []struct {
+		name string
+		// The converter file, whose known deviations are left out.
+		file    string
+		convert func(TerraformResourceData, *Config) ([]Asset, error)
+		data    map[string]interface{}
+	}{
+		{
+			name:    "GetProjectCaiObject",
+			file:    "project.go",
+			convert: GetProjectCaiObject,
+			data:    project,
+		},
+		{
+			name:    "GetProjectBillingInfoCaiObject",
+			file:    "project.go",
+			convert: GetProjectBillingInfoCaiObject,
+			data:    project,
+		},
+		{
+			name:    "GetServiceUsageCaiObject",
+			file:    "project_service.go",
+			convert: GetServiceUsageCaiObject,
+			data: map[string]interface{}{
+				"project": "my-project",
+				"service": "compute.googleapis.com",
+			},
+		},
+		{
+			name:    "GetBigtableInstanceCaiObject",
+			file:    "bigtable_instance.go",
+			convert: GetBigtableInstanceCaiObject,
+			data: map[string]interface{}{
+				"project": "my-project",
+				"name":    "my-instance",
+				"labels":  map[string]interface{}{"env": "test"},
+			},
+		},
+		{
+			name:    "GetBigtableClusterCaiObject",
+			file:    "bigtable_cluster.go",
+			convert: GetBigtableClusterCaiObject,
+			data: map[string]interface{}{
+				"project": "my-project",
+				"name":    "my-instance",
+				"cluster": []interface{}{
+					map[string]interface{}{"cluster_id": "my-cluster", "zone": "us-central1-b", "num_nodes": 3, "storage_type": "SSD"},
+				},
+			},
+		},
+		{
+			name:    "GetCloudFunctionsFunctionCaiObject",
+			file:    "cloudfunctions_function.go",
+			convert: GetCloudFunctionsFunctionCaiObject,
+			data: map[string]interface{}{
+				"project":                       "my-project",
+				"name":                          "my-function",
+				"region":                        "us-central1",
+				"runtime":                       "nodejs10",
+				"description":                   "My function",
+				"max_instances":                 10,
+				"entry_point":                   "helloWorld",
+				"labels":                        map[string]interface{}{"env": "test"},
+				"environment_variables":         map[string]interface{}{"FOO": "bar"},
+				"build_environment_variables":   map[string]interface{}{"BAZ": "qux"},
+				"available_memory_mb":           256,
+				"vpc_connector":                 "projects/my-project/locations/us-central1/connectors/my-connector",
+				"vpc_connector_egress_settings": "PRIVATE_RANGES_ONLY",
+				"ingress_settings":              "ALLOW_INTERNAL_ONLY",
+				"service_account_email":         "sa@my-project.iam.gserviceaccount.com",
+			},
+		},
+		{
+			name: "GetBigQueryTableCaiObject",
+			file: "bigquery_table.go",
+			convert: func(d TerraformResourceData, config *Config) ([]Asset, error) {
+				asset, err := GetBigQueryTableCaiObject(d, config)
+				return []Asset{asset}, err
+			},
+			data: map[string]interface{}{
+				"project":       "my-project",
+				"dataset_id":    "my_dataset",
+				"table_id":      "my_table",
+				"description":   "My table",
+				"friendly_name": "My Table",
+				"labels":        map[string]interface{}{"env": "test"},
+				"location":      "US",
+				"encryption_configuration": []interface{}{
+					map[string]interface{}{"kms_key_name": "projects/my-project/locations/us/keyRings/my-key-ring/cryptoKeys/my-key"},
+				},
+				"time_partitioning": []interface{}{
+					map[string]interface{}{"type": "DAY", "expiration_ms": 86400000, "require_partition_filter": true},
+				},
+			},
+		},
+		{
+			name:    "GetContainerClusterCaiObject",
+			file:    "container.go",
+			convert: GetContainerClusterCaiObject,
+			data: map[string]interface{}{
+				"project":                     "my-project",
+				"location":                    "us-central1",
+				"name":                        "my-cluster",
+				"description":                 "My cluster",
+				"initial_node_count":          1,
+				"enable_binary_authorization": true,
+				"enable_kubernetes_alpha":     true,
+				"enable_legacy_abac":          true,
+				"enable_tpu":                  true,
+				"pod_security_policy_config": []interface{}{
+					map[string]interface{}{"enabled": true},
+				},
+				"node_config": []interface{}{nodeConfig},
+				"master_auth": []interface{}{
+					map[string]interface{}{
+						"username": "admin",
+						"password": "hunter2hunter2hunter2",
+						"client_certificate_config": []interface{}{
+							map[string]interface{}{"issue_client_certificate": true},
+						},
+					},
+				},
+				"logging_service":    "logging.googleapis.com/kubernetes",
+				"monitoring_service": "monitoring.googleapis.com/kubernetes",
+				"network":            "default",
+				"subnetwork":         "default",
+				"private_cluster_config": []interface{}{
+					map[string]interface{}{"enable_private_nodes": true, "enable_private_endpoint": true, "master_ipv4_cidr_block": "172.16.0.0/28"},
+				},
+				"workload_identity_config": []interface{}{
+					map[string]interface{}{"identity_namespace": "my-project.svc.id.goog"},
+				},
+				"addons_config": []interface{}{
+					map[string]interface{}{
+						"http_load_balancing":        []interface{}{map[string]interface{}{"disabled": true}},
+						"horizontal_pod_autoscaling": []interface{}{map[string]interface{}{"disabled": true}},
+						"network_policy_config":      []interface{}{map[string]interface{}{"disabled": true}},
+					},
+				},
+				"node_locations":            schema.NewSet(schema.HashString, []interface{}{"us-central1-a", "us-central1-b"}),
+				"resource_labels":           map[string]interface{}{"env": "test"},
+				"default_max_pods_per_node": 32,
+				"network_policy": []interface{}{
+					map[string]interface{}{"provider": "CALICO", "enabled": true},
+				},
+				"ip_allocation_policy": []interface{}{
+					map[string]interface{}{"cluster_ipv4_cidr_block": "10.0.0.0/14", "services_ipv4_cidr_block": "10.4.0.0/19"},
+				},
+				"min_master_version": "1.16",
+				"master_authorized_networks_config": []interface{}{
+					map[string]interface{}{
+						"cidr_blocks": schema.NewSet(func(interface{}) int { return 0 }, []interface{}{
+							map[string]interface{}{"display_name": "office", "cidr_block": "192.168.0.0/24"},
+						}),
+					},
+				},
+			},
+		},
+		{
+			name:    "GetContainerNodePoolCaiObject",
+			file:    "container.go",
+			convert: GetContainerNodePoolCaiObject,
+			data: map[string]interface{}{
+				"project":            "my-project",
+				"location":           "us-central1",
+				"cluster":            "my-cluster",
+				"name":               "my-node-pool",
+				"node_config":        []interface{}{nodeConfig},
+				"initial_node_count": 1,
+				"version":            "1.16.15-gke.6000",
+				"autoscaling": []interface{}{
+					map[string]interface{}{"enabled": true, "min_node_count": 1, "max_node_count": 3},
+				},
+				"management": []interface{}{
+					map[string]interface{}{"auto_upgrade": true, "auto_repair": true},
+				},
+				"max_pods_per_node": 32,
+			},
+		},
+	}
+	for _, c := range cases {
+		assets, err := c.convert(&mockTerraformResourceData{m: c.data}, &Config{})
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if len(assets) == 0 {
+			t.Errorf("%s: no assets", c.name)
+		}
+		for _, a := range assets {
+			if a.Resource != nil {
+				for _, f := range knownDiscoverySchemaDeviations[c.file] {
+					delete(a.Resource.Data, f)
+				}
+			}
+			for _, err := range docs.CheckAsset(a) {
+				t.Errorf("%s: %v", c.name, err)
+			}
+		}
+	}
+}
+
+// converterSource is what the converters in a file name and set in asset
+// data, as read from their source.
+type converterSource struct {
+	schemas []converterSchema
+	// The string keys set in maps, such as "name" in `obj["name"] = nameProp`.
+	fields []string
+}
+
+type converterSchema struct {
+	uri, name string
+}
+
+// readConverterSources reads the converter files in dir, which are those that
+// build an AssetResource, by file name.
+func readConverterSources(dir string) (map[string]*converterSource, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]*converterSource)
+	for _, pkg := range pkgs {
+		for path, f := range pkg.Files {
+			src := &converterSource{}
+			ast.Inspect(f, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.CompositeLit:
+					if id, ok := n.Type.(*ast.Ident); !ok || id.Name != "AssetResource" {
+						return true
+					}
+					var s converterSchema
+					for _, elt := range n.Elts {
+						kv, ok := elt.(*ast.KeyValueExpr)
+						if !ok {
+							continue
+						}
+						key, _ := kv.Key.(*ast.Ident)
+						if key == nil {
+							continue
+						}
+						switch key.Name {
+						case "DiscoveryDocumentURI":
+							s.uri = stringLiteral(kv.Value)
+						case "DiscoveryName":
+							s.name = stringLiteral(kv.Value)
+						}
+					}
+					src.schemas = append(src.schemas, s)
+				case *ast.AssignStmt:
+					for _, lhs := range n.Lhs {
+						if ix, ok := lhs.(*ast.IndexExpr); ok {
+							if field := stringLiteral(ix.Index); field != "" {
+								src.fields = append(src.fields, field)
+							}
+						}
+					}
+				}
+				return true
+			})
+			if len(src.schemas) > 0 {
+				sort.Strings(src.fields)
+				sources[filepath.Base(path)] = src
+			}
+		}
+	}
+	return sources, nil
+}
+
+// stringLiteral returns the value of e if it's a string literal.
+func stringLiteral(e ast.Expr) string {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
 func TestRandString(t *testing.T) {
 	memory := make(map[string]bool)
 	for i := 0; i < 100; i++ {
@@ -79,3 +530,22 @@ func (d *mockTerraformResourceData) GetOk(k string) (interface{}, bool) {
 	v, ok := d.m[k]
 	return v, ok
 }
+
+func (d *mockTerraformResourceData) Get(k string) interface{} {
+	return d.m[k]
+}
+
+// mockAddressedResourceData is resource data read from a plan.
+type mockAddressedResourceData struct {
+	mockTerraformResourceData
+	address       string
+	unknownFields []string
+}
+
+func (d *mockAddressedResourceData) TerraformAddress() string {
+	return d.address
+}
+
+func (d *mockAddressedResourceData) UnknownFields() []string {
+	return d.unknownFields
+}
@@ -0,0 +1,725 @@
+package google
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TerraformResource is a Terraform resource converted from a CAI asset, with
+// the id it can be imported with.
+type TerraformResource struct {
+	// The resource type, such as "google_compute_instance".
+	Type string
+	// The name of the resource in the configuration.
+	Name string
+	// The id accepted by the importer of the resource type.
+	ImportId   string
+	Attributes map[string]interface{}
+	Blocks     []*TerraformBlock
+}
+
+// TerraformBlock is a nested block of a TerraformResource.
+type TerraformBlock struct {
+	Type       string
+	Attributes map[string]interface{}
+	Blocks     []*TerraformBlock
+}
+
+// Address returns the address of the resource, such as
+// "google_compute_instance.my_instance".
+func (r *TerraformResource) Address() string {
+	return r.Type + "." + r.Name
+}
+
+// Config returns the resource block of the resource.
+func (r *TerraformResource) Config() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "resource %q %q {\n", r.Type, r.Name)
+	writeTerraformBody(&b, r.Attributes, r.Blocks, "  ")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ReadCaiExport reads the assets of a CAI export, which is either a JSON array
+// of assets or one asset per line.
+func ReadCaiExport(r io.Reader) ([]Asset, error) {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+	dec.UseNumber()
+
+	// Peek at the first non-whitespace character to tell the formats apart.
+	for {
+		c, err := br.Peek(1)
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c[0] == ' ' || c[0] == '\t' || c[0] == '\r' || c[0] == '\n' {
+			if _, err := br.ReadByte(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if c[0] == '[' {
+			var assets []Asset
+			if err := dec.Decode(&assets); err != nil {
+				return nil, fmt.Errorf("decoding CAI export: %v", err)
+			}
+			return assets, nil
+		}
+		break
+	}
+
+	var assets []Asset
+	for {
+		var a Asset
+		err := dec.Decode(&a)
+		if err == io.EOF {
+			return assets, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding asset %d of CAI export: %v", len(assets)+1, err)
+		}
+		assets = append(assets, a)
+	}
+}
+
+// ConvertAssetsToTerraform converts assets to Terraform resources. Resources
+// are converted for compute instances, storage buckets, SQL instances,
+// container clusters and node pools and projects, and IAM bindings for the
+// policies of projects, folders, organizations and storage buckets. Assets
+// that nothing could be converted for are returned as unsupported.
+func ConvertAssetsToTerraform(assets []Asset) ([]*TerraformResource, []Asset, error) {
+	c := &caiToTerraformConverter{
+		projectIds: make(map[string]string),
+		names:      make(map[string]map[string]struct{}),
+	}
+	// Other assets refer to projects by number, so find their ids first.
+	for _, a := range assets {
+		if a.Type == "cloudresourcemanager.googleapis.com/Project" && a.Resource != nil {
+			number := dataString(a.Resource.Data, "projectNumber")
+			id := dataString(a.Resource.Data, "projectId")
+			if number != "" && id != "" {
+				c.projectIds[number] = id
+			}
+		}
+	}
+
+	var resources []*TerraformResource
+	var unsupported []Asset
+	for _, a := range assets {
+		converted := false
+		if f, ok := caiResourceConverters[a.Type]; ok && a.Resource != nil {
+			r, err := f(c, a)
+			if err != nil {
+				return nil, nil, fmt.Errorf("converting %s: %v", a.Name, err)
+			}
+			resources = append(resources, c.named(r))
+			converted = true
+		}
+		if f, ok := caiIamConverters[a.Type]; ok && a.IAMPolicy != nil {
+			rs, err := f(c, a)
+			if err != nil {
+				return nil, nil, fmt.Errorf("converting the IAM policy of %s: %v", a.Name, err)
+			}
+			for _, r := range rs {
+				resources = append(resources, c.named(r))
+			}
+			converted = true
+		}
+		if !converted {
+			unsupported = append(unsupported, a)
+		}
+	}
+	return resources, unsupported, nil
+}
+
+type caiToTerraformConverter struct {
+	// Project ids by project number.
+	projectIds map[string]string
+	// The names given to resources so far, by resource type.
+	names map[string]map[string]struct{}
+}
+
+// project returns the id of a project given by id or number.
+func (c *caiToTerraformConverter) project(project string) string {
+	if id, ok := c.projectIds[project]; ok {
+		return id
+	}
+	return project
+}
+
+// named gives r a valid name, unique among resources of its type, based on
+// the name it was converted with.
+func (c *caiToTerraformConverter) named(r *TerraformResource) *TerraformResource {
+	name := strings.Trim(invalidTerraformNameRe.ReplaceAllString(strings.ToLower(r.Name), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "r_" + name
+	}
+	if c.names[r.Type] == nil {
+		c.names[r.Type] = make(map[string]struct{})
+	}
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := c.names[r.Type][unique]; !ok {
+			break
+		}
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	c.names[r.Type][unique] = struct{}{}
+	r.Name = unique
+	return r
+}
+
+var invalidTerraformNameRe = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+var caiResourceConverters = map[string]func(c *caiToTerraformConverter, a Asset) (*TerraformResource, error){
+	"compute.googleapis.com/Instance":             convertComputeInstanceAsset,
+	"storage.googleapis.com/Bucket":               convertStorageBucketAsset,
+	"sqladmin.googleapis.com/Instance":            convertSqlDatabaseInstanceAsset,
+	"container.googleapis.com/Cluster":            convertContainerClusterAsset,
+	"container.googleapis.com/NodePool":           convertContainerNodePoolAsset,
+	"cloudresourcemanager.googleapis.com/Project": convertProjectAsset,
+}
+
+var caiIamConverters = map[string]func(c *caiToTerraformConverter, a Asset) ([]*TerraformResource, error){
+	"cloudresourcemanager.googleapis.com/Project":      convertProjectIamAsset,
+	"cloudresourcemanager.googleapis.com/Folder":       convertFolderIamAsset,
+	"cloudresourcemanager.googleapis.com/Organization": convertOrganizationIamAsset,
+	"storage.googleapis.com/Bucket":                    convertBucketIamAsset,
+}
+
+func convertComputeInstanceAsset(c *caiToTerraformConverter, a Asset) (*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//compute.googleapis.com/projects/{{project}}/zones/{{zone}}/instances/{{name}}")
+	if err != nil {
+		return nil, err
+	}
+	data := a.Resource.Data
+	project := c.project(parts["project"])
+
+	r := &TerraformResource{
+		Type:     "google_compute_instance",
+		Name:     parts["name"],
+		ImportId: fmt.Sprintf("projects/%s/zones/%s/instances/%s", project, parts["zone"], parts["name"]),
+		Attributes: map[string]interface{}{
+			"name":         parts["name"],
+			"project":      project,
+			"zone":         parts["zone"],
+			"machine_type": GetResourceNameFromSelfLink(dataString(data, "machineType")),
+		},
+	}
+	setAttribute(r.Attributes, "description", dataString(data, "description"))
+	setAttribute(r.Attributes, "labels", dataStringMap(data, "labels"))
+	setAttribute(r.Attributes, "tags", dataStrings(data, "tags.items"))
+	if metadata := dataList(data, "metadata.items"); len(metadata) > 0 {
+		m := make(map[string]string)
+		for _, item := range metadata {
+			m[dataString(item, "key")] = dataString(item, "value")
+		}
+		r.Attributes["metadata"] = m
+	}
+
+	for _, disk := range dataList(data, "disks") {
+		attrs := map[string]interface{}{
+			"source": dataString(disk, "source"),
+		}
+		if dataBool(disk, "boot") {
+			setAttribute(attrs, "auto_delete", disk["autoDelete"])
+			r.Blocks = append(r.Blocks, &TerraformBlock{Type: "boot_disk", Attributes: attrs})
+			continue
+		}
+		setAttribute(attrs, "device_name", dataString(disk, "deviceName"))
+		r.Blocks = append(r.Blocks, &TerraformBlock{Type: "attached_disk", Attributes: attrs})
+	}
+
+	for _, ni := range dataList(data, "networkInterfaces") {
+		b := &TerraformBlock{
+			Type:       "network_interface",
+			Attributes: map[string]interface{}{},
+		}
+		setAttribute(b.Attributes, "network", dataString(ni, "network"))
+		setAttribute(b.Attributes, "subnetwork", dataString(ni, "subnetwork"))
+		for _, ac := range dataList(ni, "accessConfigs") {
+			acb := &TerraformBlock{Type: "access_config", Attributes: map[string]interface{}{}}
+			setAttribute(acb.Attributes, "network_tier", dataString(ac, "networkTier"))
+			b.Blocks = append(b.Blocks, acb)
+		}
+		r.Blocks = append(r.Blocks, b)
+	}
+
+	for _, sa := range dataList(data, "serviceAccounts") {
+		b := &TerraformBlock{
+			Type: "service_account",
+			Attributes: map[string]interface{}{
+				"scopes": dataStrings(sa, "scopes"),
+			},
+		}
+		setAttribute(b.Attributes, "email", dataString(sa, "email"))
+		r.Blocks = append(r.Blocks, b)
+	}
+
+	return r, nil
+}
+
+func convertStorageBucketAsset(c *caiToTerraformConverter, a Asset) (*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//storage.googleapis.com/{{name}}")
+	if err != nil {
+		return nil, err
+	}
+	data := a.Resource.Data
+
+	r := &TerraformResource{
+		Type:     "google_storage_bucket",
+		Name:     parts["name"],
+		ImportId: parts["name"],
+		Attributes: map[string]interface{}{
+			"name":     parts["name"],
+			"location": dataString(data, "location"),
+		},
+	}
+	// Buckets only know the number of their project, so it's only set if the
+	// export has the project too.
+	if number := dataString(data, "projectNumber"); number != "" {
+		if id, ok := c.projectIds[number]; ok {
+			r.Attributes["project"] = id
+			r.ImportId = fmt.Sprintf("%s/%s", id, parts["name"])
+		}
+	}
+	setAttribute(r.Attributes, "storage_class", dataString(data, "storageClass"))
+	setAttribute(r.Attributes, "labels", dataStringMap(data, "labels"))
+	if dataBool(data, "iamConfiguration.uniformBucketLevelAccess.enabled") {
+		r.Attributes["uniform_bucket_level_access"] = true
+	}
+	if dataBool(data, "versioning.enabled") {
+		r.Blocks = append(r.Blocks, &TerraformBlock{
+			Type:       "versioning",
+			Attributes: map[string]interface{}{"enabled": true},
+		})
+	}
+	return r, nil
+}
+
+func convertSqlDatabaseInstanceAsset(c *caiToTerraformConverter, a Asset) (*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//cloudsql.googleapis.com/projects/{{project}}/instances/{{name}}")
+	if err != nil {
+		return nil, err
+	}
+	data := a.Resource.Data
+	project := c.project(parts["project"])
+
+	r := &TerraformResource{
+		Type:     "google_sql_database_instance",
+		Name:     parts["name"],
+		ImportId: fmt.Sprintf("projects/%s/instances/%s", project, parts["name"]),
+		Attributes: map[string]interface{}{
+			"name":    parts["name"],
+			"project": project,
+		},
+	}
+	setAttribute(r.Attributes, "region", dataString(data, "region"))
+	setAttribute(r.Attributes, "database_version", dataString(data, "databaseVersion"))
+	setAttribute(r.Attributes, "master_instance_name", dataString(data, "masterInstanceName"))
+
+	settings := &TerraformBlock{
+		Type: "settings",
+		Attributes: map[string]interface{}{
+			"tier": dataString(data, "settings.tier"),
+		},
+	}
+	setAttribute(settings.Attributes, "availability_type", dataString(data, "settings.availabilityType"))
+	setAttribute(settings.Attributes, "disk_type", dataString(data, "settings.dataDiskType"))
+	if size := dataString(data, "settings.dataDiskSizeGb"); size != "" {
+		settings.Attributes["disk_size"] = json.Number(size)
+	}
+	setAttribute(settings.Attributes, "user_labels", dataStringMap(data, "settings.userLabels"))
+	r.Blocks = append(r.Blocks, settings)
+	return r, nil
+}
+
+func convertContainerClusterAsset(c *caiToTerraformConverter, a Asset) (*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//container.googleapis.com/projects/{{project}}/locations/{{location}}/clusters/{{name}}")
+	if err != nil {
+		return nil, err
+	}
+	data := a.Resource.Data
+	project := c.project(parts["project"])
+
+	r := &TerraformResource{
+		Type:     "google_container_cluster",
+		Name:     parts["name"],
+		ImportId: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project, parts["location"], parts["name"]),
+		Attributes: map[string]interface{}{
+			"name":     parts["name"],
+			"project":  project,
+			"location": parts["location"],
+		},
+	}
+	setAttribute(r.Attributes, "description", dataString(data, "description"))
+	setAttribute(r.Attributes, "network", dataString(data, "network"))
+	setAttribute(r.Attributes, "subnetwork", dataString(data, "subnetwork"))
+	setAttribute(r.Attributes, "resource_labels", dataStringMap(data, "resourceLabels"))
+	setAttribute(r.Attributes, "min_master_version", dataString(data, "currentMasterVersion"))
+	return r, nil
+}
+
+func convertContainerNodePoolAsset(c *caiToTerraformConverter, a Asset) (*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//container.googleapis.com/projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/nodePools/{{name}}")
+	if err != nil {
+		return nil, err
+	}
+	data := a.Resource.Data
+	project := c.project(parts["project"])
+
+	r := &TerraformResource{
+		Type:     "google_container_node_pool",
+		Name:     parts["cluster"] + "_" + parts["name"],
+		ImportId: fmt.Sprintf("projects/%s/locations/%s/clusters/%s/nodePools/%s", project, parts["location"], parts["cluster"], parts["name"]),
+		Attributes: map[string]interface{}{
+			"name":     parts["name"],
+			"project":  project,
+			"location": parts["location"],
+			"cluster":  parts["cluster"],
+		},
+	}
+	if count := dataString(data, "initialNodeCount"); count != "" {
+		r.Attributes["initial_node_count"] = json.Number(count)
+	}
+	setAttribute(r.Attributes, "version", dataString(data, "version"))
+
+	if dataBool(data, "autoscaling.enabled") {
+		r.Blocks = append(r.Blocks, &TerraformBlock{
+			Type: "autoscaling",
+			Attributes: map[string]interface{}{
+				"min_node_count": dataNumber(data, "autoscaling.minNodeCount"),
+				"max_node_count": dataNumber(data, "autoscaling.maxNodeCount"),
+			},
+		})
+	}
+
+	if _, ok := data["config"]; ok {
+		nc := &TerraformBlock{Type: "node_config", Attributes: map[string]interface{}{}}
+		setAttribute(nc.Attributes, "machine_type", dataString(data, "config.machineType"))
+		if size := dataString(data, "config.diskSizeGb"); size != "" {
+			nc.Attributes["disk_size_gb"] = json.Number(size)
+		}
+		setAttribute(nc.Attributes, "service_account", dataString(data, "config.serviceAccount"))
+		setAttribute(nc.Attributes, "oauth_scopes", dataStrings(data, "config.oauthScopes"))
+		setAttribute(nc.Attributes, "labels", dataStringMap(data, "config.labels"))
+		r.Blocks = append(r.Blocks, nc)
+	}
+	return r, nil
+}
+
+func convertProjectAsset(c *caiToTerraformConverter, a Asset) (*TerraformResource, error) {
+	data := a.Resource.Data
+	id := dataString(data, "projectId")
+	if id == "" {
+		return nil, fmt.Errorf("project has no projectId")
+	}
+
+	r := &TerraformResource{
+		Type:     "google_project",
+		Name:     id,
+		ImportId: id,
+		Attributes: map[string]interface{}{
+			"project_id": id,
+			"name":       dataString(data, "name"),
+		},
+	}
+	switch dataString(data, "parent.type") {
+	case "folder":
+		r.Attributes["folder_id"] = dataString(data, "parent.id")
+	case "organization":
+		r.Attributes["org_id"] = dataString(data, "parent.id")
+	}
+	setAttribute(r.Attributes, "labels", dataStringMap(data, "labels"))
+	return r, nil
+}
+
+func convertProjectIamAsset(c *caiToTerraformConverter, a Asset) ([]*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//cloudresourcemanager.googleapis.com/projects/{{project}}")
+	if err != nil {
+		return nil, err
+	}
+	project := c.project(parts["project"])
+	return convertIamBindings(a, "google_project_iam_binding", "project", project, project), nil
+}
+
+func convertFolderIamAsset(c *caiToTerraformConverter, a Asset) ([]*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//cloudresourcemanager.googleapis.com/folders/{{folder}}")
+	if err != nil {
+		return nil, err
+	}
+	folder := "folders/" + parts["folder"]
+	return convertIamBindings(a, "google_folder_iam_binding", "folder", folder, folder), nil
+}
+
+func convertOrganizationIamAsset(c *caiToTerraformConverter, a Asset) ([]*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//cloudresourcemanager.googleapis.com/organizations/{{org_id}}")
+	if err != nil {
+		return nil, err
+	}
+	return convertIamBindings(a, "google_organization_iam_binding", "org_id", parts["org_id"], parts["org_id"]), nil
+}
+
+func convertBucketIamAsset(c *caiToTerraformConverter, a Asset) ([]*TerraformResource, error) {
+	parts, err := parseAssetName(a.Name, "//storage.googleapis.com/{{bucket}}")
+	if err != nil {
+		return nil, err
+	}
+	return convertIamBindings(a, "google_storage_bucket_iam_binding", "bucket", parts["bucket"], "b/"+parts["bucket"]), nil
+}
+
+// convertIamBindings converts each binding of the IAM policy of a to a
+// resource of resourceType, whose resourceField is value. Their import ids
+// are made of importResource, the role and the title of the condition.
+func convertIamBindings(a Asset, resourceType, resourceField, value, importResource string) []*TerraformResource {
+	var resources []*TerraformResource
+	for _, b := range a.IAMPolicy.Bindings {
+		r := &TerraformResource{
+			Type:     resourceType,
+			Name:     GetResourceNameFromSelfLink(value) + "_" + GetResourceNameFromSelfLink(b.Role),
+			ImportId: fmt.Sprintf("%s %s", importResource, b.Role),
+			Attributes: map[string]interface{}{
+				resourceField: value,
+				"role":        b.Role,
+				"members":     sortedStrings(b.Members),
+			},
+		}
+		if b.Condition != nil {
+			r.ImportId += " " + b.Condition.Title
+			r.Name += "_" + b.Condition.Title
+			cond := &TerraformBlock{
+				Type: "condition",
+				Attributes: map[string]interface{}{
+					"title":      b.Condition.Title,
+					"expression": b.Condition.Expression,
+				},
+			}
+			setAttribute(cond.Attributes, "description", b.Condition.Description)
+			r.Blocks = append(r.Blocks, cond)
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// parseAssetName returns the values of the {{fields}} of tmpl in name.
+func parseAssetName(name, tmpl string) (map[string]string, error) {
+	fieldRe := regexp.MustCompile("{{([[:word:]]+)}}")
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, m := range fieldRe.FindAllStringSubmatchIndex(tmpl, -1) {
+		pattern.WriteString(regexp.QuoteMeta(tmpl[last:m[0]]))
+		fmt.Fprintf(&pattern, "(?P<%s>[^/]+)", tmpl[m[2]:m[3]])
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(tmpl[last:]))
+	pattern.WriteString("$")
+
+	re := regexp.MustCompile(pattern.String())
+	match := re.FindStringSubmatch(name)
+	if match == nil {
+		return nil, fmt.Errorf("asset name %q doesn't match %q", name, tmpl)
+	}
+	parts := make(map[string]string)
+	for i, field := range re.SubexpNames() {
+		if field != "" {
+			parts[field] = match[i]
+		}
+	}
+	return parts, nil
+}
+
+// dataValue returns the value at path, made of keys separated by ".", in data.
+func dataValue(data map[string]interface{}, path string) interface{} {
+	var v interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// dataString returns the string, or number as a string, at path in data.
+func dataString(data map[string]interface{}, path string) string {
+	switch v := dataValue(data, path).(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// dataNumber returns the number at path in data, or 0 if there is none, as
+// APIs omit fields that are 0.
+func dataNumber(data map[string]interface{}, path string) json.Number {
+	if n := dataString(data, path); n != "" {
+		return json.Number(n)
+	}
+	return json.Number("0")
+}
+
+func dataBool(data map[string]interface{}, path string) bool {
+	b, _ := dataValue(data, path).(bool)
+	return b
+}
+
+// dataList returns the objects in the list at path in data.
+func dataList(data map[string]interface{}, path string) []map[string]interface{} {
+	l, _ := dataValue(data, path).([]interface{})
+	var objs []map[string]interface{}
+	for _, v := range l {
+		if m, ok := v.(map[string]interface{}); ok {
+			objs = append(objs, m)
+		}
+	}
+	return objs
+}
+
+func dataStrings(data map[string]interface{}, path string) []string {
+	l, _ := dataValue(data, path).([]interface{})
+	var strs []string
+	for _, v := range l {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+func dataStringMap(data map[string]interface{}, path string) map[string]string {
+	m, _ := dataValue(data, path).(map[string]interface{})
+	if len(m) == 0 {
+		return nil
+	}
+	strs := make(map[string]string)
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			strs[k] = s
+		}
+	}
+	return strs
+}
+
+// setAttribute sets attrs[key] to v, unless v is empty.
+func setAttribute(attrs map[string]interface{}, key string, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		return
+	case string:
+		if v == "" {
+			return
+		}
+	case []string:
+		if len(v) == 0 {
+			return
+		}
+	case map[string]string:
+		if len(v) == 0 {
+			return
+		}
+	}
+	attrs[key] = v
+}
+
+func sortedStrings(strs []string) []string {
+	sorted := append([]string{}, strs...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// writeTerraformBody writes attributes, in the order of their keys, then
+// blocks, each line prefixed by indent.
+func writeTerraformBody(b *bytes.Buffer, attributes map[string]interface{}, blocks []*TerraformBlock, indent string) {
+	keys := make([]string, 0, len(attributes))
+	width := 0
+	for k := range attributes {
+		keys = append(keys, k)
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, k, terraformValue(attributes[k], indent))
+	}
+
+	for _, block := range blocks {
+		b.WriteString("\n")
+		fmt.Fprintf(b, "%s%s {\n", indent, block.Type)
+		writeTerraformBody(b, block.Attributes, block.Blocks, indent+"  ")
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+}
+
+// terraformValue returns v as a Terraform expression, for an attribute at
+// indent.
+func terraformValue(v interface{}, indent string) string {
+	switch v := v.(type) {
+	case string:
+		return terraformString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		if len(v) == 0 {
+			return "[]"
+		}
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, s := range v {
+			fmt.Fprintf(&b, "%s  %s,\n", indent, terraformString(s))
+		}
+		fmt.Fprintf(&b, "%s]", indent)
+		return b.String()
+	case map[string]string:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(v))
+		width := 0
+		for k := range v {
+			keys = append(keys, k)
+			if n := len(terraformString(k)); n > width {
+				width = n
+			}
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s  %-*s = %s\n", indent, width, terraformString(k), terraformString(v[k]))
+		}
+		fmt.Fprintf(&b, "%s}", indent)
+		return b.String()
+	}
+	return terraformString(fmt.Sprintf("%v", v))
+}
+
+// terraformString returns s as a quoted Terraform string, with template
+// sequences escaped.
+func terraformString(s string) string {
+	q := strconv.Quote(s)
+	q = strings.Replace(q, "${", "$${", -1)
+	return strings.Replace(q, "%{", "%%{", -1)
+}
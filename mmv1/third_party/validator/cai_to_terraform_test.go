@@ -0,0 +1,181 @@
+package google
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const caiExportFixture = `
+{"name": "//cloudresourcemanager.googleapis.com/projects/123", "asset_type": "cloudresourcemanager.googleapis.com/Project", "resource": {"data": {"projectId": "my-project", "projectNumber": "123", "name": "My Project", "parent": {"type": "folder", "id": "456"}, "labels": {"env": "prod"}}}, "iam_policy": {"bindings": [{"role": "roles/viewer", "members": ["user:b@example.com", "user:a@example.com"]}, {"role": "roles/editor", "members": ["group:g@example.com"], "condition": {"title": "expires", "expression": "request.time < timestamp(\"2030-01-01T00:00:00Z\")"}}]}}
+{"name": "//compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/vm-1", "asset_type": "compute.googleapis.com/Instance", "resource": {"data": {"name": "vm-1", "machineType": "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/machineTypes/n1-standard-1", "tags": {"items": ["web"]}, "disks": [{"boot": true, "autoDelete": true, "source": "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/disks/vm-1"}], "networkInterfaces": [{"network": "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default", "accessConfigs": [{"networkTier": "PREMIUM"}]}]}}}
+{"name": "//storage.googleapis.com/my-bucket", "asset_type": "storage.googleapis.com/Bucket", "resource": {"data": {"name": "my-bucket", "location": "US", "projectNumber": "123", "storageClass": "STANDARD", "versioning": {"enabled": true}}}, "iam_policy": {"bindings": [{"role": "roles/storage.objectViewer", "members": ["allUsers"]}]}}
+{"name": "//cloudsql.googleapis.com/projects/my-project/instances/db", "asset_type": "sqladmin.googleapis.com/Instance", "resource": {"data": {"name": "db", "region": "us-central1", "databaseVersion": "POSTGRES_12", "settings": {"tier": "db-f1-micro", "dataDiskSizeGb": "10"}}}}
+{"name": "//container.googleapis.com/projects/my-project/locations/us-central1/clusters/gke", "asset_type": "container.googleapis.com/Cluster", "resource": {"data": {"name": "gke", "network": "default"}}}
+{"name": "//container.googleapis.com/projects/my-project/locations/us-central1/clusters/gke/nodePools/pool", "asset_type": "container.googleapis.com/NodePool", "resource": {"data": {"name": "pool", "initialNodeCount": 3, "autoscaling": {"enabled": true, "minNodeCount": 1, "maxNodeCount": 5}, "config": {"machineType": "e2-medium"}}}}
+{"name": "//container.googleapis.com/projects/my-project/locations/us-central1/clusters/gke/nodePools/scale-to-zero", "asset_type": "container.googleapis.com/NodePool", "resource": {"data": {"name": "scale-to-zero", "autoscaling": {"enabled": true, "maxNodeCount": 3}}}}
+{"name": "//cloudresourcemanager.googleapis.com/folders/456", "asset_type": "cloudresourcemanager.googleapis.com/Folder", "iam_policy": {"bindings": [{"role": "roles/browser", "members": ["user:a@example.com"]}]}}
+{"name": "//cloudresourcemanager.googleapis.com/organizations/789", "asset_type": "cloudresourcemanager.googleapis.com/Organization", "iam_policy": {"bindings": [{"role": "roles/browser", "members": ["user:a@example.com"]}]}}
+{"name": "//pubsub.googleapis.com/projects/my-project/topics/t", "asset_type": "pubsub.googleapis.com/Topic", "resource": {"data": {"name": "t"}}}
+`
+
+func TestConvertAssetsToTerraform(t *testing.T) {
+	assets, err := ReadCaiExport(strings.NewReader(caiExportFixture))
+	if err != nil {
+		t.Fatalf("reading CAI export: %v", err)
+	}
+	resources, unsupported, err := ConvertAssetsToTerraform(assets)
+	if err != nil {
+		t.Fatalf("converting assets: %v", err)
+	}
+
+	importIds := make(map[string]string)
+	for _, r := range resources {
+		importIds[r.Address()] = r.ImportId
+	}
+	expected := map[string]string{
+		"google_project.my-project":                                        "my-project",
+		"google_project_iam_binding.my-project_viewer":                     "my-project roles/viewer",
+		"google_project_iam_binding.my-project_editor_expires":             "my-project roles/editor expires",
+		"google_compute_instance.vm-1":                                     "projects/my-project/zones/us-central1-a/instances/vm-1",
+		"google_storage_bucket.my-bucket":                                  "my-project/my-bucket",
+		"google_storage_bucket_iam_binding.my-bucket_storage_objectviewer": "b/my-bucket roles/storage.objectViewer",
+		"google_sql_database_instance.db":                                  "projects/my-project/instances/db",
+		"google_container_cluster.gke":                                     "projects/my-project/locations/us-central1/clusters/gke",
+		"google_container_node_pool.gke_pool":                              "projects/my-project/locations/us-central1/clusters/gke/nodePools/pool",
+		"google_container_node_pool.gke_scale-to-zero":                     "projects/my-project/locations/us-central1/clusters/gke/nodePools/scale-to-zero",
+		"google_folder_iam_binding.r_456_browser":                          "folders/456 roles/browser",
+		"google_organization_iam_binding.r_789_browser":                    "789 roles/browser",
+	}
+	if !reflect.DeepEqual(importIds, expected) {
+		t.Errorf("import ids = %v, want %v", importIds, expected)
+	}
+
+	if len(unsupported) != 1 || unsupported[0].Type != "pubsub.googleapis.com/Topic" {
+		t.Errorf("unsupported = %v, want the pubsub topic", unsupported)
+	}
+}
+
+func TestTerraformResourceConfig(t *testing.T) {
+	assets, err := ReadCaiExport(strings.NewReader(caiExportFixture))
+	if err != nil {
+		t.Fatalf("reading CAI export: %v", err)
+	}
+	resources, _, err := ConvertAssetsToTerraform(assets)
+	if err != nil {
+		t.Fatalf("converting assets: %v", err)
+	}
+	configs := make(map[string]string)
+	for _, r := range resources {
+		configs[r.Address()] = r.Config()
+	}
+
+	cases := map[string]string{
+		"google_project.my-project": `resource "google_project" "my-project" {
+  folder_id  = "456"
+  labels     = {
+    "env" = "prod"
+  }
+  name       = "My Project"
+  project_id = "my-project"
+}
+`,
+		"google_project_iam_binding.my-project_editor_expires": `resource "google_project_iam_binding" "my-project_editor_expires" {
+  members = [
+    "group:g@example.com",
+  ]
+  project = "my-project"
+  role    = "roles/editor"
+
+  condition {
+    expression = "request.time < timestamp(\"2030-01-01T00:00:00Z\")"
+    title      = "expires"
+  }
+}
+`,
+		"google_compute_instance.vm-1": `resource "google_compute_instance" "vm-1" {
+  machine_type = "n1-standard-1"
+  name         = "vm-1"
+  project      = "my-project"
+  tags         = [
+    "web",
+  ]
+  zone         = "us-central1-a"
+
+  boot_disk {
+    auto_delete = true
+    source      = "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/disks/vm-1"
+  }
+
+  network_interface {
+    network = "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default"
+
+    access_config {
+      network_tier = "PREMIUM"
+    }
+  }
+}
+`,
+		"google_container_node_pool.gke_pool": `resource "google_container_node_pool" "gke_pool" {
+  cluster            = "gke"
+  initial_node_count = 3
+  location           = "us-central1"
+  name               = "pool"
+  project            = "my-project"
+
+  autoscaling {
+    max_node_count = 5
+    min_node_count = 1
+  }
+
+  node_config {
+    machine_type = "e2-medium"
+  }
+}
+`,
+		"google_container_node_pool.gke_scale-to-zero": `resource "google_container_node_pool" "gke_scale-to-zero" {
+  cluster  = "gke"
+  location = "us-central1"
+  name     = "scale-to-zero"
+  project  = "my-project"
+
+  autoscaling {
+    max_node_count = 3
+    min_node_count = 0
+  }
+}
+`,
+	}
+	for address, expected := range cases {
+		if got := configs[address]; got != expected {
+			t.Errorf("config of %s =\n%s\nwant\n%s", address, got, expected)
+		}
+	}
+}
+
+func TestReadCaiExport_array(t *testing.T) {
+	assets, err := ReadCaiExport(strings.NewReader(`[
+  {"name": "//storage.googleapis.com/a", "asset_type": "storage.googleapis.com/Bucket"},
+  {"name": "//storage.googleapis.com/b", "asset_type": "storage.googleapis.com/Bucket"}
+]`))
+	if err != nil {
+		t.Fatalf("reading CAI export: %v", err)
+	}
+	if len(assets) != 2 || assets[1].Name != "//storage.googleapis.com/b" {
+		t.Errorf("assets = %v, want buckets a and b", assets)
+	}
+}
+
+func TestTerraformString(t *testing.T) {
+	cases := map[string]string{
+		`plain`:            `"plain"`,
+		`"quoted"`:         `"\"quoted\""`,
+		`${var.x}`:         `"$${var.x}"`,
+		`%{if x}y%{endif}`: `"%%{if x}y%%{endif}"`,
+	}
+	for s, expected := range cases {
+		if got := terraformString(s); got != expected {
+			t.Errorf("terraformString(%q) = %s, want %s", s, got, expected)
+		}
+	}
+}
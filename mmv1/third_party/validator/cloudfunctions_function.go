@@ -86,7 +86,7 @@ func GetCloudFunctionsFunctionApiObject(d TerraformResourceData, config *Config)
 	if err != nil {
 		return nil, err
 	} else if v, ok := d.GetOkExists("build_environment_variables"); !isEmptyValue(reflect.ValueOf(buildEnvironmentVariablesProp)) && (ok || !reflect.DeepEqual(v, buildEnvironmentVariablesProp)) {
-		obj["buildEnvironmentVariablesProps"] = buildEnvironmentVariablesProp
+		obj["buildEnvironmentVariables"] = buildEnvironmentVariablesProp
 	}
 
 	availableMemoryMbProp, err := expandCloudFunctionsFunctionMemoryMb(d.Get("available_memory_mb"), d, config)
@@ -276,18 +276,6 @@ func GetContainerClusterApiObject(d TerraformResourceData, config *Config) (map[
 	} else if v, ok := d.GetOkExists("location"); !isEmptyValue(reflect.ValueOf(locationProp)) && (ok || !reflect.DeepEqual(v, locationProp)) {
 		obj["location"] = locationProp
 	}
-	kubectlPathProp, err := expandContainerClusterKubectlPath(d.Get("kubectl_path"), d, config)
-	if err != nil {
-		return nil, err
-	} else if v, ok := d.GetOkExists("kubectl_path"); !isEmptyValue(reflect.ValueOf(kubectlPathProp)) && (ok || !reflect.DeepEqual(v, kubectlPathProp)) {
-		obj["kubectlPath"] = kubectlPathProp
-	}
-	kubectlContextProp, err := expandContainerClusterKubectlContext(d.Get("kubectl_context"), d, config)
-	if err != nil {
-		return nil, err
-	} else if v, ok := d.GetOkExists("kubectl_context"); !isEmptyValue(reflect.ValueOf(kubectlContextProp)) && (ok || !reflect.DeepEqual(v, kubectlContextProp)) {
-		obj["kubectlContext"] = kubectlContextProp
-	}
 
 	return obj, nil
 }
@@ -1156,14 +1144,6 @@ func expandContainerClusterLocation(v interface{}, d TerraformResourceData, conf
 	return v, nil
 }
 
-func expandContainerClusterKubectlPath(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
-	return v, nil
-}
-
-func expandContainerClusterKubectlContext(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
-	return v, nil
-}
-
 func GetContainerNodePoolCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
 	name, err := assetName(d, config, "//container.googleapis.com/projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/nodePools/{{name}}")
 	if err != nil {
@@ -0,0 +1,302 @@
+package google
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiscoveryDocuments are discovery documents loaded from a local directory,
+// against which converted assets are checked.
+type DiscoveryDocuments struct {
+	// Documents by id, such as "compute:v1".
+	byId map[string]*discoveryDocument
+	// Documents by API name, such as "compute".
+	byName map[string][]*discoveryDocument
+}
+
+type discoveryDocument struct {
+	Id      string                      `json:"id"`
+	Name    string                      `json:"name"`
+	Version string                      `json:"version"`
+	Schemas map[string]*discoverySchema `json:"schemas"`
+}
+
+type discoverySchema struct {
+	Ref                  string                      `json:"$ref"`
+	Type                 string                      `json:"type"`
+	Format               string                      `json:"format"`
+	Enum                 []string                    `json:"enum"`
+	Properties           map[string]*discoverySchema `json:"properties"`
+	AdditionalProperties *discoverySchema            `json:"additionalProperties"`
+	Items                *discoverySchema            `json:"items"`
+}
+
+// The two forms of DiscoveryDocumentURI used by converters.
+var (
+	discoveryApisUriRe    = regexp.MustCompile(`^https://www\.googleapis\.com/discovery/v1/apis/([^/]+)/([^/]+)/rest$`)
+	discoveryServiceUriRe = regexp.MustCompile(`^https://([^./]+)\.googleapis\.com/\$discovery/rest(\?.*)?$`)
+)
+
+// LoadDiscoveryDocuments loads the discovery documents in the .json files of
+// dir.
+func LoadDiscoveryDocuments(dir string) (*DiscoveryDocuments, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := &DiscoveryDocuments{
+		byId:   make(map[string]*discoveryDocument),
+		byName: make(map[string][]*discoveryDocument),
+	}
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		doc := &discoveryDocument{}
+		if err := json.Unmarshal(b, doc); err != nil {
+			return nil, fmt.Errorf("error parsing discovery document %s: %v", f.Name(), err)
+		}
+		if doc.Name == "" || doc.Version == "" {
+			return nil, fmt.Errorf("%s is not a discovery document", f.Name())
+		}
+		docs.byId[doc.Name+":"+doc.Version] = doc
+		docs.byName[doc.Name] = append(docs.byName[doc.Name], doc)
+	}
+	return docs, nil
+}
+
+// document returns the discovery document at uri.
+func (d *DiscoveryDocuments) document(uri string) (*discoveryDocument, error) {
+	if m := discoveryApisUriRe.FindStringSubmatch(uri); m != nil {
+		if doc, ok := d.byId[m[1]+":"+m[2]]; ok {
+			return doc, nil
+		}
+		return nil, fmt.Errorf("no discovery document for %s %s, from %s", m[1], m[2], uri)
+	}
+
+	if m := discoveryServiceUriRe.FindStringSubmatch(uri); m != nil {
+		if m[2] != "" {
+			q, err := url.ParseQuery(strings.TrimPrefix(m[2], "?"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid discovery document uri %s: %v", uri, err)
+			}
+			if v := q.Get("version"); v != "" {
+				if doc, ok := d.byId[m[1]+":"+v]; ok {
+					return doc, nil
+				}
+				return nil, fmt.Errorf("no discovery document for %s %s, from %s", m[1], v, uri)
+			}
+		}
+		// Without a version, the API is expected to have a single one.
+		switch docs := d.byName[m[1]]; len(docs) {
+		case 0:
+			return nil, fmt.Errorf("no discovery document for %s, from %s", m[1], uri)
+		case 1:
+			return docs[0], nil
+		default:
+			return nil, fmt.Errorf("%s doesn't specify which of the %d versions of %s it is", uri, len(docs), m[1])
+		}
+	}
+
+	return nil, fmt.Errorf("unrecognized discovery document uri %s", uri)
+}
+
+// schema returns the schema named name in the discovery document at uri.
+func (d *DiscoveryDocuments) schema(uri, name string) (*discoveryDocument, *discoverySchema, error) {
+	doc, err := d.document(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, ok := doc.Schemas[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("discovery document %s has no schema %s", doc.Id, name)
+	}
+	return doc, s, nil
+}
+
+// fieldNames returns the names of the properties of the schema named name in
+// the discovery document at uri, and of the schemas nested in it.
+func (d *DiscoveryDocuments) fieldNames(uri, name string) (map[string]struct{}, error) {
+	doc, s, err := d.schema(uri, name)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	var walk func(s *discoverySchema)
+	walk = func(s *discoverySchema) {
+		if s == nil {
+			return
+		}
+		if s.Ref != "" {
+			if _, ok := seen[s.Ref]; ok {
+				return
+			}
+			seen[s.Ref] = struct{}{}
+			walk(doc.Schemas[s.Ref])
+			return
+		}
+		for n, p := range s.Properties {
+			names[n] = struct{}{}
+			walk(p)
+		}
+		walk(s.AdditionalProperties)
+		walk(s.Items)
+	}
+	seen[name] = struct{}{}
+	walk(s)
+	return names, nil
+}
+
+// CheckAsset checks that the Data of a conforms to the schema named by its
+// DiscoveryName in the document at its DiscoveryDocumentURI. It returns an
+// error for each field that isn't in the schema, or whose value has the wrong
+// type or isn't one of the field's enum values.
+func (d *DiscoveryDocuments) CheckAsset(a Asset) []error {
+	if a.Resource == nil {
+		return nil
+	}
+	doc, s, err := d.schema(a.Resource.DiscoveryDocumentURI, a.Resource.DiscoveryName)
+	if err != nil {
+		return []error{fmt.Errorf("%s: %v", a.Name, err)}
+	}
+
+	// Check the data as it's serialized, since that's what policies see.
+	b, err := json.Marshal(a.Resource.Data)
+	if err != nil {
+		return []error{fmt.Errorf("%s: error serializing data: %v", a.Name, err)}
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return []error{fmt.Errorf("%s: error deserializing data: %v", a.Name, err)}
+	}
+
+	c := &schemaChecker{doc: doc}
+	c.check("", s, data)
+	var errs []error
+	for _, e := range c.errs {
+		errs = append(errs, fmt.Errorf("%s: %s", a.Name, e))
+	}
+	return errs
+}
+
+type schemaChecker struct {
+	doc  *discoveryDocument
+	errs []string
+}
+
+func (c *schemaChecker) errorf(path, format string, a ...interface{}) {
+	if path == "" {
+		path = "data"
+	}
+	c.errs = append(c.errs, path+": "+fmt.Sprintf(format, a...))
+}
+
+// check checks v, at path, against s.
+func (c *schemaChecker) check(path string, s *discoverySchema, v interface{}) {
+	if v == nil {
+		return
+	}
+	if s.Ref != "" {
+		ref, ok := c.doc.Schemas[s.Ref]
+		if !ok {
+			c.errorf(path, "discovery document %s has no schema %s", c.doc.Id, s.Ref)
+			return
+		}
+		s = ref
+	}
+
+	switch s.Type {
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			c.errorf(path, "expected an object, got %v", v)
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p, ok := s.Properties[k]
+			if !ok {
+				p = s.AdditionalProperties
+			}
+			if p == nil {
+				c.errorf(joinFieldPath(path, k), "unknown field")
+				continue
+			}
+			c.check(joinFieldPath(path, k), p, m[k])
+		}
+	case "array":
+		l, ok := v.([]interface{})
+		if !ok {
+			c.errorf(path, "expected an array, got %v", v)
+			return
+		}
+		if s.Items == nil {
+			return
+		}
+		for i, item := range l {
+			c.check(joinFieldPath(path, strconv.Itoa(i)), s.Items, item)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			// 64 bit integers are strings in JSON, but converters often set
+			// them as numbers.
+			if n, isNumber := v.(json.Number); isNumber && (s.Format == "int64" || s.Format == "uint64") {
+				if _, err := n.Int64(); err == nil {
+					return
+				}
+			}
+			c.errorf(path, "expected a string, got %v", v)
+			return
+		}
+		if len(s.Enum) > 0 && !stringInSlice(s.Enum, str) {
+			c.errorf(path, "%q is not one of %s", str, strings.Join(s.Enum, ", "))
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			c.errorf(path, "expected an integer, got %v", v)
+			return
+		}
+		if _, err := n.Int64(); err != nil {
+			c.errorf(path, "expected an integer, got %v", v)
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			c.errorf(path, "expected a number, got %v", v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			c.errorf(path, "expected a boolean, got %v", v)
+		}
+	}
+}
+
+// joinFieldPath joins field paths with ".", as in
+// "networkInterfaces.0.network".
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,135 @@
+package google
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDiscoveryDocument = `{
+  "id": "widgets:v1",
+  "name": "widgets",
+  "version": "v1",
+  "schemas": {
+    "Widget": {
+      "id": "Widget",
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "size": {"type": "integer", "format": "int32"},
+        "weight": {"type": "string", "format": "int64"},
+        "enabled": {"type": "boolean"},
+        "state": {"type": "string", "enum": ["ACTIVE", "DELETED"]},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+        "parts": {"type": "array", "items": {"$ref": "Part"}}
+      }
+    },
+    "Part": {
+      "id": "Part",
+      "type": "object",
+      "properties": {
+        "partName": {"type": "string"}
+      }
+    }
+  }
+}`
+
+func TestDiscoveryDocumentsCheckAsset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "widgets-v1.json"), []byte(testDiscoveryDocument), 0644); err != nil {
+		t.Fatal(err)
+	}
+	docs, err := LoadDiscoveryDocuments(dir)
+	if err != nil {
+		t.Fatalf("loading discovery documents: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		uri      string
+		schema   string
+		data     map[string]interface{}
+		expected []string
+	}{
+		{
+			name:   "Conforming",
+			uri:    "https://www.googleapis.com/discovery/v1/apis/widgets/v1/rest",
+			schema: "Widget",
+			data: map[string]interface{}{
+				"name":    "w",
+				"size":    3,
+				"weight":  int64(1 << 40),
+				"enabled": true,
+				"state":   "ACTIVE",
+				"labels":  map[string]string{"a": "b"},
+				"parts":   []interface{}{map[string]interface{}{"partName": "p"}},
+			},
+		},
+		{
+			name:   "ServiceUri",
+			uri:    "https://widgets.googleapis.com/$discovery/rest?version=v1",
+			schema: "Widget",
+			data:   map[string]interface{}{"name": "w"},
+		},
+		{
+			name:   "Nonconforming",
+			uri:    "https://www.googleapis.com/discovery/v1/apis/widgets/v1/rest",
+			schema: "Widget",
+			data: map[string]interface{}{
+				"nmae":    "w",
+				"size":    "3",
+				"enabled": "true",
+				"state":   "GONE",
+				"parts":   []interface{}{map[string]interface{}{"part_name": "p"}},
+			},
+			expected: []string{
+				"enabled: expected a boolean",
+				"nmae: unknown field",
+				"parts.0.part_name: unknown field",
+				"size: expected an integer",
+				`state: "GONE" is not one of ACTIVE, DELETED`,
+			},
+		},
+		{
+			name:     "UnknownDocument",
+			uri:      "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+			schema:   "Widget",
+			data:     map[string]interface{}{},
+			expected: []string{"no discovery document for compute v1"},
+		},
+		{
+			name:     "UnknownSchema",
+			uri:      "https://www.googleapis.com/discovery/v1/apis/widgets/v1/rest",
+			schema:   "Gadget",
+			data:     map[string]interface{}{},
+			expected: []string{"discovery document widgets:v1 has no schema Gadget"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs := docs.CheckAsset(Asset{
+				Name: "//widgets.googleapis.com/widgets/w",
+				Resource: &AssetResource{
+					DiscoveryDocumentURI: c.uri,
+					DiscoveryName:        c.schema,
+					Data:                 c.data,
+				},
+			})
+			if len(errs) != len(c.expected) {
+				t.Fatalf("got errors %v, want %d errors", errs, len(c.expected))
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), c.expected[i]) {
+					t.Errorf("error %d = %q, want it to contain %q", i, err, c.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,19 @@
 package google
 
-import "fmt"
-
 func GetFolderIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newFolderIamAsset(d, config, expandIamPolicyBindings)
+	return newFolderIamAsset(d, config, expandIamPolicyBindings, expandIamPolicyAuditConfigs)
 }
 
 func GetFolderIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newFolderIamAsset(d, config, expandIamRoleBindings)
+	return newFolderIamAsset(d, config, expandIamRoleBindings, nil)
 }
 
 func GetFolderIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newFolderIamAsset(d, config, expandIamMemberBindings)
+	return newFolderIamAsset(d, config, expandIamMemberBindings, nil)
+}
+
+func GetFolderIamAuditConfigCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newFolderIamAsset(d, config, nil, expandIamAuditConfigs)
 }
 
 func MergeFolderIamPolicy(existing, incoming Asset) Asset {
@@ -35,14 +37,23 @@ func MergeFolderIamMemberDelete(existing, incoming Asset) Asset {
 	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
 }
 
+func MergeFolderIamAuditConfig(existing, incoming Asset) Asset {
+	return mergeIamAuditConfigAssets(existing, incoming, mergeAuthoritativeAuditConfigs)
+}
+
+func MergeFolderIamAuditConfigDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAuditConfigAssets(existing, incoming, mergeDeleteAuthoritativeAuditConfigs)
+}
+
 func newFolderIamAsset(
 	d TerraformResourceData,
 	config *Config,
 	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+	expandAuditConfigs func(d TerraformResourceData) ([]IAMAuditConfig, error),
 ) ([]Asset, error) {
-	bindings, err := expandBindings(d)
+	policy, err := expandIamPolicy(d, expandBindings, expandAuditConfigs)
 	if err != nil {
-		return []Asset{}, fmt.Errorf("expanding bindings: %v", err)
+		return []Asset{}, err
 	}
 
 	// The "folder" argument is of the form "folders/12345"
@@ -52,11 +63,9 @@ func newFolderIamAsset(
 	}
 
 	return []Asset{{
-		Name: name,
-		Type: "cloudresourcemanager.googleapis.com/Folder",
-		IAMPolicy: &IAMPolicy{
-			Bindings: bindings,
-		},
+		Name:      name,
+		Type:      "cloudresourcemanager.googleapis.com/Folder",
+		IAMPolicy: policy,
 	}}, nil
 }
 
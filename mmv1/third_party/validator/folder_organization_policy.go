@@ -0,0 +1,33 @@
+package google
+
+import "strings"
+
+func GetFolderOrgPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	// The "folder" argument may be either "folders/12345" or "12345".
+	tmpl := "//cloudresourcemanager.googleapis.com/{{folder}}"
+	if folder := d.Get("folder").(string); folder != "" && !strings.HasPrefix(folder, "folders/") {
+		tmpl = "//cloudresourcemanager.googleapis.com/folders/{{folder}}"
+	}
+	name, err := assetName(d, config, tmpl)
+	if err != nil {
+		return []Asset{}, err
+	}
+	if obj, err := GetFolderOrgPolicyApiObject(d, config); err == nil {
+		return []Asset{{
+			Name:      name,
+			Type:      "cloudresourcemanager.googleapis.com/Folder",
+			OrgPolicy: []*OrgPolicy{&obj},
+		}}, nil
+	} else {
+		return []Asset{}, err
+	}
+}
+
+func MergeFolderOrgPolicy(existing, incoming Asset) Asset {
+	existing.OrgPolicy = append(existing.OrgPolicy, incoming.OrgPolicy...)
+	return existing
+}
+
+func GetFolderOrgPolicyApiObject(d TerraformResourceData, config *Config) (OrgPolicy, error) {
+	return expandOrgPolicy(d)
+}
@@ -0,0 +1,65 @@
+package google
+
+func GetHealthcareDatasetIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareDatasetIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetHealthcareDatasetIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareDatasetIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetHealthcareDatasetIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareDatasetIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeHealthcareDatasetIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeHealthcareDatasetIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeHealthcareDatasetIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeHealthcareDatasetIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeHealthcareDatasetIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newHealthcareDatasetIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	tmpl, err := healthcareDatasetIamAssetNameTmpl(d, config)
+	if err != nil {
+		return []Asset{}, err
+	}
+	return newResourceIamAsset(d, config, expandBindings, tmpl, "healthcare.googleapis.com/Dataset")
+}
+
+func FetchHealthcareDatasetIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	tmpl, err := healthcareDatasetIamAssetNameTmpl(d, config)
+	if err != nil {
+		return Asset{}, err
+	}
+	return fetchIamPolicy(
+		NewHealthcareDatasetIamUpdater,
+		d,
+		config,
+		tmpl,
+		"healthcare.googleapis.com/Dataset",
+	)
+}
+
+// healthcareDatasetIamAssetNameTmpl resolves dataset_id, which may be in a short form.
+func healthcareDatasetIamAssetNameTmpl(d TerraformResourceData, config *Config) (string, error) {
+	return iamUpdaterAssetNameTmpl(d, config, NewHealthcareDatasetIamUpdater, "healthcare.googleapis.com", "dataset_id")
+}
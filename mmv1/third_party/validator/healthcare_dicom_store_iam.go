@@ -0,0 +1,65 @@
+package google
+
+func GetHealthcareDicomStoreIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareDicomStoreIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetHealthcareDicomStoreIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareDicomStoreIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetHealthcareDicomStoreIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareDicomStoreIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeHealthcareDicomStoreIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeHealthcareDicomStoreIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeHealthcareDicomStoreIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeHealthcareDicomStoreIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeHealthcareDicomStoreIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newHealthcareDicomStoreIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	tmpl, err := healthcareDicomStoreIamAssetNameTmpl(d, config)
+	if err != nil {
+		return []Asset{}, err
+	}
+	return newResourceIamAsset(d, config, expandBindings, tmpl, "healthcare.googleapis.com/DicomStore")
+}
+
+func FetchHealthcareDicomStoreIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	tmpl, err := healthcareDicomStoreIamAssetNameTmpl(d, config)
+	if err != nil {
+		return Asset{}, err
+	}
+	return fetchIamPolicy(
+		NewHealthcareDicomStoreIamUpdater,
+		d,
+		config,
+		tmpl,
+		"healthcare.googleapis.com/DicomStore",
+	)
+}
+
+// healthcareDicomStoreIamAssetNameTmpl resolves dicom_store_id, which may be in a short form.
+func healthcareDicomStoreIamAssetNameTmpl(d TerraformResourceData, config *Config) (string, error) {
+	return iamUpdaterAssetNameTmpl(d, config, NewHealthcareDicomStoreIamUpdater, "healthcare.googleapis.com", "dicom_store_id")
+}
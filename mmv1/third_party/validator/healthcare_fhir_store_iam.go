@@ -0,0 +1,65 @@
+package google
+
+func GetHealthcareFhirStoreIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareFhirStoreIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetHealthcareFhirStoreIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareFhirStoreIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetHealthcareFhirStoreIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareFhirStoreIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeHealthcareFhirStoreIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeHealthcareFhirStoreIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeHealthcareFhirStoreIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeHealthcareFhirStoreIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeHealthcareFhirStoreIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newHealthcareFhirStoreIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	tmpl, err := healthcareFhirStoreIamAssetNameTmpl(d, config)
+	if err != nil {
+		return []Asset{}, err
+	}
+	return newResourceIamAsset(d, config, expandBindings, tmpl, "healthcare.googleapis.com/FhirStore")
+}
+
+func FetchHealthcareFhirStoreIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	tmpl, err := healthcareFhirStoreIamAssetNameTmpl(d, config)
+	if err != nil {
+		return Asset{}, err
+	}
+	return fetchIamPolicy(
+		NewHealthcareFhirStoreIamUpdater,
+		d,
+		config,
+		tmpl,
+		"healthcare.googleapis.com/FhirStore",
+	)
+}
+
+// healthcareFhirStoreIamAssetNameTmpl resolves fhir_store_id, which may be in a short form.
+func healthcareFhirStoreIamAssetNameTmpl(d TerraformResourceData, config *Config) (string, error) {
+	return iamUpdaterAssetNameTmpl(d, config, NewHealthcareFhirStoreIamUpdater, "healthcare.googleapis.com", "fhir_store_id")
+}
@@ -0,0 +1,65 @@
+package google
+
+func GetHealthcareHl7V2StoreIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareHl7V2StoreIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetHealthcareHl7V2StoreIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareHl7V2StoreIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetHealthcareHl7V2StoreIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newHealthcareHl7V2StoreIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeHealthcareHl7V2StoreIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeHealthcareHl7V2StoreIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeHealthcareHl7V2StoreIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeHealthcareHl7V2StoreIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeHealthcareHl7V2StoreIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newHealthcareHl7V2StoreIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	tmpl, err := healthcareHl7V2StoreIamAssetNameTmpl(d, config)
+	if err != nil {
+		return []Asset{}, err
+	}
+	return newResourceIamAsset(d, config, expandBindings, tmpl, "healthcare.googleapis.com/Hl7V2Store")
+}
+
+func FetchHealthcareHl7V2StoreIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	tmpl, err := healthcareHl7V2StoreIamAssetNameTmpl(d, config)
+	if err != nil {
+		return Asset{}, err
+	}
+	return fetchIamPolicy(
+		NewHealthcareHl7V2StoreIamUpdater,
+		d,
+		config,
+		tmpl,
+		"healthcare.googleapis.com/Hl7V2Store",
+	)
+}
+
+// healthcareHl7V2StoreIamAssetNameTmpl resolves hl7_v2_store_id, which may be in a short form.
+func healthcareHl7V2StoreIamAssetNameTmpl(d TerraformResourceData, config *Config) (string, error) {
+	return iamUpdaterAssetNameTmpl(d, config, NewHealthcareHl7V2StoreIamUpdater, "healthcare.googleapis.com", "hl7_v2_store_id")
+}
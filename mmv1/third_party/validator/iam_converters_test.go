@@ -0,0 +1,140 @@
+package google
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceIamAssetNames(t *testing.T) {
+	cases := []struct {
+		name    string
+		convert func(d TerraformResourceData, config *Config) ([]Asset, error)
+		data    map[string]interface{}
+		// Expected outputs
+		expectedName string
+		expectedType string
+	}{
+		{
+			name:         "ServiceAccount",
+			convert:      GetServiceAccountIamMemberCaiObject,
+			data:         map[string]interface{}{"service_account_id": "projects/my-project/serviceAccounts/sa@my-project.iam.gserviceaccount.com"},
+			expectedName: "//iam.googleapis.com/projects/my-project/serviceAccounts/sa@my-project.iam.gserviceaccount.com",
+			expectedType: "iam.googleapis.com/ServiceAccount",
+		},
+		{
+			name:         "KmsKeyRing",
+			convert:      GetKmsKeyRingIamMemberCaiObject,
+			data:         map[string]interface{}{"key_ring_id": "other-project/us-central1/my-key-ring"},
+			expectedName: "//cloudkms.googleapis.com/projects/other-project/locations/us-central1/keyRings/my-key-ring",
+			expectedType: "cloudkms.googleapis.com/KeyRing",
+		},
+		{
+			name:         "KmsKeyRingWithoutProject",
+			convert:      GetKmsKeyRingIamMemberCaiObject,
+			data:         map[string]interface{}{"key_ring_id": "us-central1/my-key-ring"},
+			expectedName: "//cloudkms.googleapis.com/projects/my-project/locations/us-central1/keyRings/my-key-ring",
+			expectedType: "cloudkms.googleapis.com/KeyRing",
+		},
+		{
+			name:         "KmsCryptoKey",
+			convert:      GetKmsCryptoKeyIamMemberCaiObject,
+			data:         map[string]interface{}{"crypto_key_id": "projects/other-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key"},
+			expectedName: "//cloudkms.googleapis.com/projects/other-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key",
+			expectedType: "cloudkms.googleapis.com/CryptoKey",
+		},
+		{
+			name:         "KmsCryptoKeyWithoutProject",
+			convert:      GetKmsCryptoKeyIamMemberCaiObject,
+			data:         map[string]interface{}{"crypto_key_id": "us-central1/my-key-ring/my-key"},
+			expectedName: "//cloudkms.googleapis.com/projects/my-project/locations/us-central1/keyRings/my-key-ring/cryptoKeys/my-key",
+			expectedType: "cloudkms.googleapis.com/CryptoKey",
+		},
+		{
+			name:         "PubsubSubscription",
+			convert:      GetPubsubSubscriptionIamMemberCaiObject,
+			data:         map[string]interface{}{"subscription": "projects/other-project/subscriptions/my-subscription"},
+			expectedName: "//pubsub.googleapis.com/projects/other-project/subscriptions/my-subscription",
+			expectedType: "pubsub.googleapis.com/Subscription",
+		},
+		{
+			name:         "PubsubSubscriptionShortName",
+			convert:      GetPubsubSubscriptionIamMemberCaiObject,
+			data:         map[string]interface{}{"subscription": "my-subscription"},
+			expectedName: "//pubsub.googleapis.com/projects/my-project/subscriptions/my-subscription",
+			expectedType: "pubsub.googleapis.com/Subscription",
+		},
+		{
+			name:         "SpannerInstance",
+			convert:      GetSpannerInstanceIamMemberCaiObject,
+			data:         map[string]interface{}{"project": "my-project", "instance": "my-instance"},
+			expectedName: "//spanner.googleapis.com/projects/my-project/instances/my-instance",
+			expectedType: "spanner.googleapis.com/Instance",
+		},
+		{
+			name:         "SpannerDatabase",
+			convert:      GetSpannerDatabaseIamMemberCaiObject,
+			data:         map[string]interface{}{"project": "my-project", "instance": "my-instance", "database": "my-database"},
+			expectedName: "//spanner.googleapis.com/projects/my-project/instances/my-instance/databases/my-database",
+			expectedType: "spanner.googleapis.com/Database",
+		},
+		{
+			name:         "BigqueryDataset",
+			convert:      GetBigqueryDatasetIamMemberCaiObject,
+			data:         map[string]interface{}{"project": "my-project", "dataset_id": "my_dataset"},
+			expectedName: "//bigquery.googleapis.com/projects/my-project/datasets/my_dataset",
+			expectedType: "bigquery.googleapis.com/Dataset",
+		},
+		{
+			name:         "HealthcareDataset",
+			convert:      GetHealthcareDatasetIamMemberCaiObject,
+			data:         map[string]interface{}{"dataset_id": "other-project/us-central1/my-dataset"},
+			expectedName: "//healthcare.googleapis.com/projects/other-project/locations/us-central1/datasets/my-dataset",
+			expectedType: "healthcare.googleapis.com/Dataset",
+		},
+		{
+			name:         "HealthcareDatasetWithoutProject",
+			convert:      GetHealthcareDatasetIamMemberCaiObject,
+			data:         map[string]interface{}{"dataset_id": "us-central1/my-dataset"},
+			expectedName: "//healthcare.googleapis.com/projects/my-project/locations/us-central1/datasets/my-dataset",
+			expectedType: "healthcare.googleapis.com/Dataset",
+		},
+		{
+			name:         "HealthcareDicomStore",
+			convert:      GetHealthcareDicomStoreIamMemberCaiObject,
+			data:         map[string]interface{}{"dicom_store_id": "us-central1/my-dataset/my-store"},
+			expectedName: "//healthcare.googleapis.com/projects/my-project/locations/us-central1/datasets/my-dataset/dicomStores/my-store",
+			expectedType: "healthcare.googleapis.com/DicomStore",
+		},
+		{
+			name:         "HealthcareFhirStore",
+			convert:      GetHealthcareFhirStoreIamMemberCaiObject,
+			data:         map[string]interface{}{"fhir_store_id": "projects/other-project/locations/us-central1/datasets/my-dataset/fhirStores/my-store"},
+			expectedName: "//healthcare.googleapis.com/projects/other-project/locations/us-central1/datasets/my-dataset/fhirStores/my-store",
+			expectedType: "healthcare.googleapis.com/FhirStore",
+		},
+		{
+			name:         "HealthcareHl7V2Store",
+			convert:      GetHealthcareHl7V2StoreIamMemberCaiObject,
+			data:         map[string]interface{}{"hl7_v2_store_id": "other-project/us-central1/my-dataset/my-store"},
+			expectedName: "//healthcare.googleapis.com/projects/other-project/locations/us-central1/datasets/my-dataset/hl7V2Stores/my-store",
+			expectedType: "healthcare.googleapis.com/Hl7V2Store",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.data["role"] = "roles/viewer"
+			c.data["member"] = "user:jane@example.com"
+			assets, err := c.convert(&mockTerraformResourceData{m: c.data}, &Config{Project: "my-project"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !assert.Len(t, assets, 1) {
+				return
+			}
+			assert.Equal(t, c.expectedName, assets[0].Name)
+			assert.Equal(t, c.expectedType, assets[0].Type)
+			assert.Equal(t, []IAMBinding{{Role: "roles/viewer", Members: []string{"user:jane@example.com"}}}, assets[0].IAMPolicy.Bindings)
+		})
+	}
+}
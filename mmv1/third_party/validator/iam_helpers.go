@@ -9,20 +9,30 @@ import (
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 )
 
-// expandIamPolicyBindings is used in google_<type>_iam_policy resources.
-func expandIamPolicyBindings(d TerraformResourceData) ([]IAMBinding, error) {
+// expandIamPolicyData returns the policy of google_<type>_iam_policy resources.
+func expandIamPolicyData(d TerraformResourceData) (*cloudresourcemanager.Policy, error) {
 	ps := d.Get("policy_data").(string)
 	// The policy string is just a marshaled cloudresourcemanager.Policy.
 	policy := &cloudresourcemanager.Policy{}
 	if err := json.Unmarshal([]byte(ps), policy); err != nil {
 		return nil, fmt.Errorf("Could not unmarshal %s:\n: %v", ps, err)
 	}
+	return policy, nil
+}
+
+// expandIamPolicyBindings is used in google_<type>_iam_policy resources.
+func expandIamPolicyBindings(d TerraformResourceData) ([]IAMBinding, error) {
+	policy, err := expandIamPolicyData(d)
+	if err != nil {
+		return nil, err
+	}
 
 	var bindings []IAMBinding
 	for _, b := range policy.Bindings {
 		bindings = append(bindings, IAMBinding{
-			Role:    b.Role,
-			Members: b.Members,
+			Role:      b.Role,
+			Members:   b.Members,
+			Condition: iamConditionFromExpr(b.Condition),
 		})
 	}
 
@@ -37,8 +47,9 @@ func expandIamRoleBindings(d TerraformResourceData) ([]IAMBinding, error) {
 	}
 	return []IAMBinding{
 		{
-			Role:    d.Get("role").(string),
-			Members: members,
+			Role:      d.Get("role").(string),
+			Members:   members,
+			Condition: expandIamBindingCondition(d),
 		},
 	}, nil
 }
@@ -47,12 +58,132 @@ func expandIamRoleBindings(d TerraformResourceData) ([]IAMBinding, error) {
 func expandIamMemberBindings(d TerraformResourceData) ([]IAMBinding, error) {
 	return []IAMBinding{
 		{
-			Role:    d.Get("role").(string),
-			Members: []string{d.Get("member").(string)},
+			Role:      d.Get("role").(string),
+			Members:   []string{d.Get("member").(string)},
+			Condition: expandIamBindingCondition(d),
+		},
+	}, nil
+}
+
+// expandIamPolicyAuditConfigs is used in google_<type>_iam_policy resources.
+func expandIamPolicyAuditConfigs(d TerraformResourceData) ([]IAMAuditConfig, error) {
+	policy, err := expandIamPolicyData(d)
+	if err != nil {
+		return nil, err
+	}
+	return iamAuditConfigsFromPolicy(policy.AuditConfigs), nil
+}
+
+// expandIamAuditConfigs is used in google_<type>_iam_audit_config resources.
+func expandIamAuditConfigs(d TerraformResourceData) ([]IAMAuditConfig, error) {
+	var logConfigs []IAMAuditLogConfig
+	for _, raw := range d.Get("audit_log_config").(*schema.Set).List() {
+		c := raw.(map[string]interface{})
+		var members []string
+		if s, ok := c["exempted_members"].(*schema.Set); ok {
+			for _, m := range s.List() {
+				members = append(members, m.(string))
+			}
+		}
+		sort.Strings(members)
+		logConfigs = append(logConfigs, IAMAuditLogConfig{
+			LogType:         c["log_type"].(string),
+			ExemptedMembers: members,
+		})
+	}
+	sortAuditLogConfigs(logConfigs)
+
+	return []IAMAuditConfig{
+		{
+			Service:         d.Get("service").(string),
+			AuditLogConfigs: logConfigs,
 		},
 	}, nil
 }
 
+// iamAuditConfigsFromPolicy converts the audit configs of a
+// cloudresourcemanager Policy.
+func iamAuditConfigsFromPolicy(acs []*cloudresourcemanager.AuditConfig) []IAMAuditConfig {
+	var auditConfigs []IAMAuditConfig
+	for _, ac := range acs {
+		var logConfigs []IAMAuditLogConfig
+		for _, alc := range ac.AuditLogConfigs {
+			members := append([]string{}, alc.ExemptedMembers...)
+			if len(members) == 0 {
+				members = nil
+			}
+			sort.Strings(members)
+			logConfigs = append(logConfigs, IAMAuditLogConfig{
+				LogType:         alc.LogType,
+				ExemptedMembers: members,
+			})
+		}
+		sortAuditLogConfigs(logConfigs)
+		auditConfigs = append(auditConfigs, IAMAuditConfig{
+			Service:         ac.Service,
+			AuditLogConfigs: logConfigs,
+		})
+	}
+	return auditConfigs
+}
+
+func sortAuditLogConfigs(logConfigs []IAMAuditLogConfig) {
+	sort.Slice(logConfigs, func(i, j int) bool {
+		return logConfigs[i].LogType < logConfigs[j].LogType
+	})
+}
+
+// expandIamBindingCondition returns the condition block of a
+// google_<type>_iam_binding or google_<type>_iam_member resource, or nil if it
+// is unset.
+func expandIamBindingCondition(d TerraformResourceData) *IAMCondition {
+	l, ok := d.Get("condition").([]interface{})
+	if !ok || len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	c := l[0].(map[string]interface{})
+	condition := &IAMCondition{}
+	if v, ok := c["expression"].(string); ok {
+		condition.Expression = v
+	}
+	if v, ok := c["title"].(string); ok {
+		condition.Title = v
+	}
+	if v, ok := c["description"].(string); ok {
+		condition.Description = v
+	}
+	return condition
+}
+
+// iamConditionFromExpr converts the condition of a cloudresourcemanager
+// Binding, returning nil if there is none.
+func iamConditionFromExpr(e *cloudresourcemanager.Expr) *IAMCondition {
+	if e == nil {
+		return nil
+	}
+	return &IAMCondition{
+		Expression:  e.Expression,
+		Title:       e.Title,
+		Description: e.Description,
+	}
+}
+
+// bindingKey returns the role+condition key of a binding, matching the keys
+// used to merge bindings in the provider's IAM resources.
+func bindingKey(b IAMBinding) iamBindingKey {
+	if b.Condition == nil {
+		return iamBindingKey{Role: b.Role}
+	}
+	return iamBindingKey{
+		Role: b.Role,
+		Condition: conditionKey{
+			Description: b.Condition.Description,
+			Expression:  b.Condition.Expression,
+			Title:       b.Condition.Title,
+		},
+	}
+}
+
 // mergeIamAssets merges an existing asset with the IAM bindings of an incoming
 // Asset.
 func mergeIamAssets(
@@ -78,16 +209,41 @@ func mergeDeleteIamAssets(
 	return existing
 }
 
-// mergeAdditiveBindings adds members to bindings with the same roles and adds new
-// bindings for roles that dont exist.
+// mergeIamAuditConfigAssets merges an existing asset with the IAM audit
+// configs of an incoming Asset.
+func mergeIamAuditConfigAssets(
+	existing, incoming Asset,
+	mergeAuditConfigs func(existing, incoming []IAMAuditConfig) []IAMAuditConfig,
+) Asset {
+	if existing.IAMPolicy != nil {
+		existing.IAMPolicy.AuditConfigs = mergeAuditConfigs(existing.IAMPolicy.AuditConfigs, incoming.IAMPolicy.AuditConfigs)
+	} else {
+		existing.IAMPolicy = incoming.IAMPolicy
+	}
+	return existing
+}
+
+// incoming is the last known state of an asset prior to deletion
+func mergeDeleteIamAuditConfigAssets(
+	existing, incoming Asset,
+	mergeAuditConfigs func(existing, incoming []IAMAuditConfig) []IAMAuditConfig,
+) Asset {
+	if existing.IAMPolicy != nil {
+		existing.IAMPolicy.AuditConfigs = mergeAuditConfigs(existing.IAMPolicy.AuditConfigs, incoming.IAMPolicy.AuditConfigs)
+	}
+	return existing
+}
+
+// mergeAdditiveBindings adds members to bindings with the same role and
+// condition and adds new bindings for role+conditions that dont exist.
 func mergeAdditiveBindings(existing, incoming []IAMBinding) []IAMBinding {
-	existingIdxs := make(map[string]int)
+	existingIdxs := make(map[iamBindingKey]int)
 	for i, binding := range existing {
-		existingIdxs[binding.Role] = i
+		existingIdxs[bindingKey(binding)] = i
 	}
 
 	for _, binding := range incoming {
-		if ei, ok := existingIdxs[binding.Role]; ok {
+		if ei, ok := existingIdxs[bindingKey(binding)]; ok {
 			memberExists := make(map[string]bool)
 			for _, m := range existing[ei].Members {
 				memberExists[m] = true
@@ -111,13 +267,20 @@ func mergeAdditiveBindings(existing, incoming []IAMBinding) []IAMBinding {
 	return existing
 }
 
-// mergeDeleteAdditiveBindings eliminates listed members from roles in the
-// existing list. incoming is the last known state of the bindings being deleted.
+// iamMemberKey identifies a single member of a role+condition binding.
+type iamMemberKey struct {
+	Binding iamBindingKey
+	Member  string
+}
+
+// mergeDeleteAdditiveBindings eliminates listed members from role+conditions in
+// the existing list. incoming is the last known state of the bindings being
+// deleted.
 func mergeDeleteAdditiveBindings(existing, incoming []IAMBinding) []IAMBinding {
-	toDelete := make(map[string]struct{})
+	toDelete := make(map[iamMemberKey]struct{})
 	for _, binding := range incoming {
 		for _, m := range binding.Members {
-			key := binding.Role + "-" + m
+			key := iamMemberKey{bindingKey(binding), m}
 			toDelete[key] = struct{}{}
 		}
 	}
@@ -126,7 +289,7 @@ func mergeDeleteAdditiveBindings(existing, incoming []IAMBinding) []IAMBinding {
 	for _, binding := range existing {
 		var newMembers []string
 		for _, m := range binding.Members {
-			key := binding.Role + "-" + m
+			key := iamMemberKey{bindingKey(binding), m}
 			_, delete := toDelete[key]
 			if !delete {
 				newMembers = append(newMembers, m)
@@ -134,8 +297,9 @@ func mergeDeleteAdditiveBindings(existing, incoming []IAMBinding) []IAMBinding {
 		}
 		if newMembers != nil {
 			newExisting = append(newExisting, IAMBinding{
-				Role: binding.Role,
-				Members: newMembers,
+				Role:      binding.Role,
+				Members:   newMembers,
+				Condition: binding.Condition,
 			})
 		}
 	}
@@ -143,16 +307,16 @@ func mergeDeleteAdditiveBindings(existing, incoming []IAMBinding) []IAMBinding {
 	return newExisting
 }
 
-// mergeAuthoritativeBindings clobbers members to bindings with the same roles
-// and adds new bindings for roles that dont exist.
+// mergeAuthoritativeBindings clobbers members to bindings with the same role
+// and condition and adds new bindings for role+conditions that dont exist.
 func mergeAuthoritativeBindings(existing, incoming []IAMBinding) []IAMBinding {
-	existingIdxs := make(map[string]int)
+	existingIdxs := make(map[iamBindingKey]int)
 	for i, binding := range existing {
-		existingIdxs[binding.Role] = i
+		existingIdxs[bindingKey(binding)] = i
 	}
 
 	for _, binding := range incoming {
-		if ei, ok := existingIdxs[binding.Role]; ok {
+		if ei, ok := existingIdxs[bindingKey(binding)]; ok {
 			existing[ei].Members = binding.Members
 		} else {
 			existing = append(existing, binding)
@@ -167,19 +331,19 @@ func mergeAuthoritativeBindings(existing, incoming []IAMBinding) []IAMBinding {
 	return existing
 }
 
-// mergeDeleteAuthoritativeBindings eliminates any bindings with matching roles
-// in the existing list. incoming is the last known state of the bindings being
-// deleted.
+// mergeDeleteAuthoritativeBindings eliminates any bindings with matching role
+// and condition in the existing list. incoming is the last known state of the
+// bindings being deleted.
 func mergeDeleteAuthoritativeBindings(existing, incoming []IAMBinding) []IAMBinding {
-	toDelete := make(map[string]struct{})
+	toDelete := make(map[iamBindingKey]struct{})
 	for _, binding := range incoming {
-		key := binding.Role
+		key := bindingKey(binding)
 		toDelete[key] = struct{}{}
 	}
 
 	var newExisting []IAMBinding
 	for _, binding := range existing {
-		key := binding.Role
+		key := bindingKey(binding)
 		_, delete := toDelete[key]
 		if !delete {
 			newExisting = append(newExisting, binding)
@@ -189,6 +353,45 @@ func mergeDeleteAuthoritativeBindings(existing, incoming []IAMBinding) []IAMBind
 	return newExisting
 }
 
+// mergeAuthoritativeAuditConfigs clobbers audit configs with the same service
+// and adds new audit configs for services that dont exist.
+func mergeAuthoritativeAuditConfigs(existing, incoming []IAMAuditConfig) []IAMAuditConfig {
+	existingIdxs := make(map[string]int)
+	for i, ac := range existing {
+		existingIdxs[ac.Service] = i
+	}
+
+	for _, ac := range incoming {
+		if ei, ok := existingIdxs[ac.Service]; ok {
+			existing[ei].AuditLogConfigs = ac.AuditLogConfigs
+		} else {
+			existing = append(existing, ac)
+		}
+	}
+
+	return existing
+}
+
+// mergeDeleteAuthoritativeAuditConfigs eliminates any audit configs with
+// matching services in the existing list. incoming is the last known state of
+// the audit configs being deleted.
+func mergeDeleteAuthoritativeAuditConfigs(existing, incoming []IAMAuditConfig) []IAMAuditConfig {
+	toDelete := make(map[string]struct{})
+	for _, ac := range incoming {
+		toDelete[ac.Service] = struct{}{}
+	}
+
+	var newExisting []IAMAuditConfig
+	for _, ac := range existing {
+		_, delete := toDelete[ac.Service]
+		if !delete {
+			newExisting = append(newExisting, ac)
+		}
+	}
+
+	return newExisting
+}
+
 func fetchIamPolicy(
 	newUpdaterFunc newResourceIamUpdaterFunc,
 	d TerraformResourceData,
@@ -211,8 +414,9 @@ func fetchIamPolicy(
 		bindings = append(
 			bindings,
 			IAMBinding{
-				Role:    b.Role,
-				Members: b.Members,
+				Role:      b.Role,
+				Members:   b.Members,
+				Condition: iamConditionFromExpr(b.Condition),
 			},
 		)
 	}
@@ -223,7 +427,83 @@ func fetchIamPolicy(
 		Name: name,
 		Type: assetType,
 		IAMPolicy: &IAMPolicy{
-			Bindings: bindings,
+			Bindings:     bindings,
+			AuditConfigs: iamAuditConfigsFromPolicy(iamPolicy.AuditConfigs),
 		},
 	}, nil
 }
+
+// expandIamPolicy returns the IAM policy with the bindings and audit configs
+// returned by the expanders. Resources manage either bindings, audit configs
+// or both, so either expander may be nil.
+func expandIamPolicy(
+	d TerraformResourceData,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+	expandAuditConfigs func(d TerraformResourceData) ([]IAMAuditConfig, error),
+) (*IAMPolicy, error) {
+	policy := &IAMPolicy{}
+	if expandBindings != nil {
+		bindings, err := expandBindings(d)
+		if err != nil {
+			return nil, fmt.Errorf("expanding bindings: %v", err)
+		}
+		policy.Bindings = bindings
+	}
+	if expandAuditConfigs != nil {
+		auditConfigs, err := expandAuditConfigs(d)
+		if err != nil {
+			return nil, fmt.Errorf("expanding audit configs: %v", err)
+		}
+		policy.AuditConfigs = auditConfigs
+	}
+	return policy, nil
+}
+
+// newResourceIamAsset returns the asset of the IAM policy of a resource named
+// by assetNameTmpl, with the bindings returned by expandBindings.
+func newResourceIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+	assetNameTmpl string,
+	assetType string,
+) ([]Asset, error) {
+	bindings, err := expandBindings(d)
+	if err != nil {
+		return []Asset{}, fmt.Errorf("expanding bindings: %v", err)
+	}
+
+	name, err := assetName(d, config, assetNameTmpl)
+	if err != nil {
+		return []Asset{}, err
+	}
+
+	return []Asset{{
+		Name: name,
+		Type: assetType,
+		IAMPolicy: &IAMPolicy{
+			Bindings: bindings,
+		},
+	}}, nil
+}
+
+// iamUpdaterAssetNameTmpl returns the asset name template of a resource whose
+// id, in idField, may be in a short form such as "{location}/{name}". The
+// name is the id, as the resource's ResourceIamUpdater resolves it, prefixed
+// by "//<service>/". An unknown id is templated so that it gets a placeholder.
+func iamUpdaterAssetNameTmpl(
+	d TerraformResourceData,
+	config *Config,
+	newUpdaterFunc newResourceIamUpdaterFunc,
+	service string,
+	idField string,
+) (string, error) {
+	if id, _ := d.Get(idField).(string); id == "" {
+		return fmt.Sprintf("//%s/{{%s}}", service, idField), nil
+	}
+	updater, err := newUpdaterFunc(d, config)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("//%s/%s", service, updater.GetResourceId()), nil
+}
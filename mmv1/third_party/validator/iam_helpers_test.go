@@ -94,6 +94,85 @@ func TestMergeBindings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ConditionalAddOne",
+			existing: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-b"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2021-01-01T00:00:00Z\")",
+						Title:      "expires",
+					},
+				},
+			},
+			incoming: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-c"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2021-01-01T00:00:00Z\")",
+						Title:      "expires",
+					},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-d"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2022-01-01T00:00:00Z\")",
+						Title:      "expires-later",
+					},
+				},
+			},
+			expectedAdditive: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-b", "member-c"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2021-01-01T00:00:00Z\")",
+						Title:      "expires",
+					},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-d"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2022-01-01T00:00:00Z\")",
+						Title:      "expires-later",
+					},
+				},
+			},
+			expectedAuthoritative: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-c"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2021-01-01T00:00:00Z\")",
+						Title:      "expires",
+					},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-d"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2022-01-01T00:00:00Z\")",
+						Title:      "expires-later",
+					},
+				},
+			},
+		},
 		{
 			name: "GrandFinale",
 			existing: []IAMBinding{
@@ -239,6 +318,53 @@ func TestMergeDeleteBindings(t *testing.T) {
 			},
 			expectedDeleteAuthoritative: nil,
 		},
+		{
+			name: "ConditionalDeleteOne",
+			existing: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2021-01-01T00:00:00Z\")",
+						Title:      "expires",
+					},
+				},
+			},
+			incoming: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-b"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2021-01-01T00:00:00Z\")",
+						Title:      "expires",
+					},
+				},
+			},
+			expectedDeleteAdditive: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+					Condition: &IAMCondition{
+						Expression: "request.time < timestamp(\"2021-01-01T00:00:00Z\")",
+						Title:      "expires",
+					},
+				},
+			},
+			expectedDeleteAuthoritative: []IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+			},
+		},
 		{
 			name: "GrandFinale",
 			existing: []IAMBinding{
@@ -298,3 +424,146 @@ func TestMergeDeleteBindings(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeAuditConfigs(t *testing.T) {
+	cases := []struct {
+		name string
+		// Inputs
+		existing []IAMAuditConfig
+		incoming []IAMAuditConfig
+		// Expected outputs
+		expectedAuthoritative       []IAMAuditConfig
+		expectedDeleteAuthoritative []IAMAuditConfig
+	}{
+		{
+			name:                        "EmptyAddEmpty",
+			existing:                    []IAMAuditConfig{},
+			incoming:                    []IAMAuditConfig{},
+			expectedAuthoritative:       []IAMAuditConfig{},
+			expectedDeleteAuthoritative: nil,
+		},
+		{
+			name:     "EmptyAddOne",
+			existing: []IAMAuditConfig{},
+			incoming: []IAMAuditConfig{
+				{
+					Service:         "service-a",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_READ"}},
+				},
+			},
+			expectedAuthoritative: []IAMAuditConfig{
+				{
+					Service:         "service-a",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_READ"}},
+				},
+			},
+			expectedDeleteAuthoritative: nil,
+		},
+		{
+			name: "GrandFinale",
+			existing: []IAMAuditConfig{
+				{
+					Service: "service-a",
+					AuditLogConfigs: []IAMAuditLogConfig{
+						{LogType: "ADMIN_READ"},
+						{LogType: "DATA_READ", ExemptedMembers: []string{"member-a"}},
+					},
+				},
+				{
+					Service:         "service-b",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_WRITE"}},
+				},
+			},
+			incoming: []IAMAuditConfig{
+				{
+					Service:         "service-a",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_WRITE"}},
+				},
+				{
+					Service:         "service-c",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_READ"}},
+				},
+			},
+			expectedAuthoritative: []IAMAuditConfig{
+				{
+					Service:         "service-a",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_WRITE"}},
+				},
+				{
+					Service:         "service-b",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_WRITE"}},
+				},
+				{
+					Service:         "service-c",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_READ"}},
+				},
+			},
+			expectedDeleteAuthoritative: []IAMAuditConfig{
+				{
+					Service:         "service-b",
+					AuditLogConfigs: []IAMAuditLogConfig{{LogType: "DATA_WRITE"}},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		// Each merge may modify existing, so give each its own copy.
+		t.Run(c.name+"/mergeDeleteAuthoritativeAuditConfigs", func(t *testing.T) {
+			assert.EqualValues(t,
+				c.expectedDeleteAuthoritative,
+				mergeDeleteAuthoritativeAuditConfigs(append([]IAMAuditConfig{}, c.existing...), c.incoming),
+			)
+		})
+		t.Run(c.name+"/mergeAuthoritativeAuditConfigs", func(t *testing.T) {
+			assert.EqualValues(t,
+				c.expectedAuthoritative,
+				mergeAuthoritativeAuditConfigs(append([]IAMAuditConfig{}, c.existing...), c.incoming),
+			)
+		})
+	}
+}
+
+// fakeIamUpdater is a ResourceIamUpdater of a KMS key ring in "p/l".
+type fakeIamUpdater struct {
+	ResourceIamUpdater
+	keyRing string
+}
+
+func (u *fakeIamUpdater) GetResourceId() string {
+	return "projects/p/locations/l/keyRings/" + u.keyRing
+}
+
+func TestIamUpdaterAssetNameTmpl(t *testing.T) {
+	newUpdater := func(d TerraformResourceData, config *Config) (ResourceIamUpdater, error) {
+		return &fakeIamUpdater{keyRing: d.Get("key_ring_id").(string)}, nil
+	}
+
+	cases := []struct {
+		name     string
+		keyRing  string
+		expected string
+	}{
+		{
+			name:     "Known",
+			keyRing:  "key-ring",
+			expected: "//cloudkms.googleapis.com/projects/p/locations/l/keyRings/key-ring",
+		},
+		{
+			name:     "Unknown",
+			keyRing:  "",
+			expected: "//cloudkms.googleapis.com/{{key_ring_id}}",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := &mockTerraformResourceData{
+				m: map[string]interface{}{"key_ring_id": c.keyRing},
+			}
+			tmpl, err := iamUpdaterAssetNameTmpl(d, nil, newUpdater, "cloudkms.googleapis.com", "key_ring_id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, c.expected, tmpl)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package google
+
+func GetKmsCryptoKeyIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newKmsCryptoKeyIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetKmsCryptoKeyIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newKmsCryptoKeyIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetKmsCryptoKeyIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newKmsCryptoKeyIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeKmsCryptoKeyIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeKmsCryptoKeyIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeKmsCryptoKeyIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeKmsCryptoKeyIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeKmsCryptoKeyIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newKmsCryptoKeyIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	tmpl, err := kmsCryptoKeyIamAssetNameTmpl(d, config)
+	if err != nil {
+		return []Asset{}, err
+	}
+	return newResourceIamAsset(d, config, expandBindings, tmpl, "cloudkms.googleapis.com/CryptoKey")
+}
+
+func FetchKmsCryptoKeyIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	tmpl, err := kmsCryptoKeyIamAssetNameTmpl(d, config)
+	if err != nil {
+		return Asset{}, err
+	}
+	return fetchIamPolicy(
+		NewKmsCryptoKeyIamUpdater,
+		d,
+		config,
+		tmpl,
+		"cloudkms.googleapis.com/CryptoKey",
+	)
+}
+
+// kmsCryptoKeyIamAssetNameTmpl resolves crypto_key_id, which may be in a short form.
+func kmsCryptoKeyIamAssetNameTmpl(d TerraformResourceData, config *Config) (string, error) {
+	return iamUpdaterAssetNameTmpl(d, config, NewKmsCryptoKeyIamUpdater, "cloudkms.googleapis.com", "crypto_key_id")
+}
@@ -0,0 +1,65 @@
+package google
+
+func GetKmsKeyRingIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newKmsKeyRingIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetKmsKeyRingIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newKmsKeyRingIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetKmsKeyRingIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newKmsKeyRingIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeKmsKeyRingIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeKmsKeyRingIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeKmsKeyRingIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeKmsKeyRingIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeKmsKeyRingIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newKmsKeyRingIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	tmpl, err := kmsKeyRingIamAssetNameTmpl(d, config)
+	if err != nil {
+		return []Asset{}, err
+	}
+	return newResourceIamAsset(d, config, expandBindings, tmpl, "cloudkms.googleapis.com/KeyRing")
+}
+
+func FetchKmsKeyRingIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	tmpl, err := kmsKeyRingIamAssetNameTmpl(d, config)
+	if err != nil {
+		return Asset{}, err
+	}
+	return fetchIamPolicy(
+		NewKmsKeyRingIamUpdater,
+		d,
+		config,
+		tmpl,
+		"cloudkms.googleapis.com/KeyRing",
+	)
+}
+
+// kmsKeyRingIamAssetNameTmpl resolves key_ring_id, which may be in a short form.
+func kmsKeyRingIamAssetNameTmpl(d TerraformResourceData, config *Config) (string, error) {
+	return iamUpdaterAssetNameTmpl(d, config, NewKmsKeyRingIamUpdater, "cloudkms.googleapis.com", "key_ring_id")
+}
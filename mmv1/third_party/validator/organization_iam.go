@@ -1,17 +1,19 @@
 package google
 
-import "fmt"
-
 func GetOrganizationIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newOrganizationIamAsset(d, config, expandIamPolicyBindings)
+	return newOrganizationIamAsset(d, config, expandIamPolicyBindings, expandIamPolicyAuditConfigs)
 }
 
 func GetOrganizationIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newOrganizationIamAsset(d, config, expandIamRoleBindings)
+	return newOrganizationIamAsset(d, config, expandIamRoleBindings, nil)
 }
 
 func GetOrganizationIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newOrganizationIamAsset(d, config, expandIamMemberBindings)
+	return newOrganizationIamAsset(d, config, expandIamMemberBindings, nil)
+}
+
+func GetOrganizationIamAuditConfigCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newOrganizationIamAsset(d, config, nil, expandIamAuditConfigs)
 }
 
 func MergeOrganizationIamPolicy(existing, incoming Asset) Asset {
@@ -35,14 +37,23 @@ func MergeOrganizationIamMemberDelete(existing, incoming Asset) Asset {
 	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
 }
 
+func MergeOrganizationIamAuditConfig(existing, incoming Asset) Asset {
+	return mergeIamAuditConfigAssets(existing, incoming, mergeAuthoritativeAuditConfigs)
+}
+
+func MergeOrganizationIamAuditConfigDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAuditConfigAssets(existing, incoming, mergeDeleteAuthoritativeAuditConfigs)
+}
+
 func newOrganizationIamAsset(
 	d TerraformResourceData,
 	config *Config,
 	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+	expandAuditConfigs func(d TerraformResourceData) ([]IAMAuditConfig, error),
 ) ([]Asset, error) {
-	bindings, err := expandBindings(d)
+	policy, err := expandIamPolicy(d, expandBindings, expandAuditConfigs)
 	if err != nil {
-		return []Asset{}, fmt.Errorf("expanding bindings: %v", err)
+		return []Asset{}, err
 	}
 
 	name, err := assetName(d, config, "//cloudresourcemanager.googleapis.com/organizations/{{org_id}}")
@@ -51,11 +62,9 @@ func newOrganizationIamAsset(
 	}
 
 	return []Asset{{
-		Name: name,
-		Type: "cloudresourcemanager.googleapis.com/Organization",
-		IAMPolicy: &IAMPolicy{
-			Bindings: bindings,
-		},
+		Name:      name,
+		Type:      "cloudresourcemanager.googleapis.com/Organization",
+		IAMPolicy: policy,
 	}}, nil
 }
 
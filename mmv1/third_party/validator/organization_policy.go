@@ -0,0 +1,26 @@
+package google
+
+func GetOrganizationOrgPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	name, err := assetName(d, config, "//cloudresourcemanager.googleapis.com/organizations/{{org_id}}")
+	if err != nil {
+		return []Asset{}, err
+	}
+	if obj, err := GetOrganizationOrgPolicyApiObject(d, config); err == nil {
+		return []Asset{{
+			Name:      name,
+			Type:      "cloudresourcemanager.googleapis.com/Organization",
+			OrgPolicy: []*OrgPolicy{&obj},
+		}}, nil
+	} else {
+		return []Asset{}, err
+	}
+}
+
+func MergeOrganizationOrgPolicy(existing, incoming Asset) Asset {
+	existing.OrgPolicy = append(existing.OrgPolicy, incoming.OrgPolicy...)
+	return existing
+}
+
+func GetOrganizationOrgPolicyApiObject(d TerraformResourceData, config *Config) (OrgPolicy, error) {
+	return expandOrgPolicy(d)
+}
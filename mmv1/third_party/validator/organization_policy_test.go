@@ -0,0 +1,164 @@
+package google
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgPolicyAssetNames(t *testing.T) {
+	cases := []struct {
+		name         string
+		convert      func(TerraformResourceData, *Config) ([]Asset, error)
+		data         map[string]interface{}
+		expectedName string
+		expectedType string
+	}{
+		{
+			name:         "FolderWithPrefix",
+			convert:      GetFolderOrgPolicyCaiObject,
+			data:         map[string]interface{}{"folder": "folders/123"},
+			expectedName: "//cloudresourcemanager.googleapis.com/folders/123",
+			expectedType: "cloudresourcemanager.googleapis.com/Folder",
+		},
+		{
+			name:         "FolderWithoutPrefix",
+			convert:      GetFolderOrgPolicyCaiObject,
+			data:         map[string]interface{}{"folder": "123"},
+			expectedName: "//cloudresourcemanager.googleapis.com/folders/123",
+			expectedType: "cloudresourcemanager.googleapis.com/Folder",
+		},
+		{
+			name:         "Organization",
+			convert:      GetOrganizationOrgPolicyCaiObject,
+			data:         map[string]interface{}{"org_id": "456"},
+			expectedName: "//cloudresourcemanager.googleapis.com/organizations/456",
+			expectedType: "cloudresourcemanager.googleapis.com/Organization",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.data["constraint"] = "compute.disableSerialPortAccess"
+			c.data["boolean_policy"] = []interface{}{map[string]interface{}{"enforced": true}}
+			c.data["list_policy"] = []interface{}{}
+			c.data["restore_policy"] = []interface{}{}
+
+			assets, err := c.convert(&mockTerraformResourceData{m: c.data}, &Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !assert.Len(t, assets, 1) {
+				return
+			}
+			assert.Equal(t, c.expectedName, assets[0].Name)
+			assert.Equal(t, c.expectedType, assets[0].Type)
+			if assert.Len(t, assets[0].OrgPolicy, 1) {
+				assert.Equal(t, "constraints/compute.disableSerialPortAccess", assets[0].OrgPolicy[0].Constraint)
+			}
+		})
+	}
+}
+
+func TestExpandOrgPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     map[string]interface{}
+		expected OrgPolicy
+		wantErr  bool
+	}{
+		{
+			name: "BooleanPolicy",
+			data: map[string]interface{}{
+				"constraint":     "constraints/compute.disableSerialPortAccess",
+				"boolean_policy": []interface{}{map[string]interface{}{"enforced": true}},
+			},
+			expected: OrgPolicy{
+				Constraint:    "constraints/compute.disableSerialPortAccess",
+				BooleanPolicy: &BooleanPolicy{Enforced: true},
+			},
+		},
+		{
+			name: "ListPolicyAllowedValues",
+			data: map[string]interface{}{
+				"constraint": "serviceuser.services",
+				"list_policy": []interface{}{map[string]interface{}{
+					"allow": []interface{}{map[string]interface{}{
+						"all":    false,
+						"values": schema.NewSet(schema.HashString, []interface{}{"compute.googleapis.com"}),
+					}},
+					"deny":                []interface{}{},
+					"suggested_value":     "compute.googleapis.com",
+					"inherit_from_parent": true,
+				}},
+			},
+			expected: OrgPolicy{
+				Constraint: "constraints/serviceuser.services",
+				ListPolicy: &ListPolicy{
+					AllowedValues:     []string{"compute.googleapis.com"},
+					SuggestedValue:    "compute.googleapis.com",
+					InheritFromParent: true,
+				},
+			},
+		},
+		{
+			name: "ListPolicyAllowAll",
+			data: map[string]interface{}{
+				"constraint": "constraints/serviceuser.services",
+				"list_policy": []interface{}{map[string]interface{}{
+					"allow": []interface{}{map[string]interface{}{
+						"all":    true,
+						"values": schema.NewSet(schema.HashString, nil),
+					}},
+					"deny":                []interface{}{},
+					"suggested_value":     "",
+					"inherit_from_parent": false,
+				}},
+			},
+			expected: OrgPolicy{
+				Constraint: "constraints/serviceuser.services",
+				ListPolicy: &ListPolicy{AllValues: 1},
+			},
+		},
+		{
+			name: "RestoreDefault",
+			data: map[string]interface{}{
+				"constraint":     "constraints/compute.disableSerialPortAccess",
+				"restore_policy": []interface{}{map[string]interface{}{"default": true}},
+			},
+			expected: OrgPolicy{
+				Constraint:     "constraints/compute.disableSerialPortAccess",
+				RestoreDefault: &RestoreDefault{},
+			},
+		},
+		{
+			name: "RestoreWithoutDefault",
+			data: map[string]interface{}{
+				"constraint":     "constraints/compute.disableSerialPortAccess",
+				"restore_policy": []interface{}{map[string]interface{}{"default": false}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, k := range []string{"boolean_policy", "list_policy", "restore_policy"} {
+				if _, ok := c.data[k]; !ok {
+					c.data[k] = []interface{}{}
+				}
+			}
+
+			got, err := expandOrgPolicy(&mockTerraformResourceData{m: c.data})
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
@@ -27,7 +27,7 @@ func GetProjectCaiObject(d TerraformResourceData, config *Config) ([]Asset, erro
 			Type: "cloudresourcemanager.googleapis.com/Project",
 			Resource: &AssetResource{
 				Version:              "v1",
-				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/cloudresourcemanager/v1/rest",
 				DiscoveryName:        "Project",
 				Data:                 obj,
 			},
@@ -1,17 +1,19 @@
 package google
 
-import "fmt"
-
 func GetProjectIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newProjectIamAsset(d, config, expandIamPolicyBindings)
+	return newProjectIamAsset(d, config, expandIamPolicyBindings, expandIamPolicyAuditConfigs)
 }
 
 func GetProjectIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newProjectIamAsset(d, config, expandIamRoleBindings)
+	return newProjectIamAsset(d, config, expandIamRoleBindings, nil)
 }
 
 func GetProjectIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
-	return newProjectIamAsset(d, config, expandIamMemberBindings)
+	return newProjectIamAsset(d, config, expandIamMemberBindings, nil)
+}
+
+func GetProjectIamAuditConfigCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newProjectIamAsset(d, config, nil, expandIamAuditConfigs)
 }
 
 func MergeProjectIamPolicy(existing, incoming Asset) Asset {
@@ -35,14 +37,23 @@ func MergeProjectIamMemberDelete(existing, incoming Asset) Asset {
 	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
 }
 
+func MergeProjectIamAuditConfig(existing, incoming Asset) Asset {
+	return mergeIamAuditConfigAssets(existing, incoming, mergeAuthoritativeAuditConfigs)
+}
+
+func MergeProjectIamAuditConfigDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAuditConfigAssets(existing, incoming, mergeDeleteAuthoritativeAuditConfigs)
+}
+
 func newProjectIamAsset(
 	d TerraformResourceData,
 	config *Config,
 	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+	expandAuditConfigs func(d TerraformResourceData) ([]IAMAuditConfig, error),
 ) ([]Asset, error) {
-	bindings, err := expandBindings(d)
+	policy, err := expandIamPolicy(d, expandBindings, expandAuditConfigs)
 	if err != nil {
-		return []Asset{}, fmt.Errorf("expanding bindings: %v", err)
+		return []Asset{}, err
 	}
 
 	// Ideally we should use project_number, but since that is generated server-side,
@@ -53,11 +64,9 @@ func newProjectIamAsset(
 	}
 
 	return []Asset{{
-		Name: name,
-		Type: "cloudresourcemanager.googleapis.com/Project",
-		IAMPolicy: &IAMPolicy{
-			Bindings: bindings,
-		},
+		Name:      name,
+		Type:      "cloudresourcemanager.googleapis.com/Project",
+		IAMPolicy: policy,
 	}}, nil
 }
 
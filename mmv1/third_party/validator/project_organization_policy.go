@@ -29,7 +29,12 @@ func MergeProjectOrgPolicy(existing, incoming Asset) Asset {
 }
 
 func GetProjectOrgPolicyApiObject(d TerraformResourceData, config *Config) (OrgPolicy, error) {
+	return expandOrgPolicy(d)
+}
 
+// expandOrgPolicy is used in google_<type>_organization_policy resources, which
+// share the same policy schema.
+func expandOrgPolicy(d TerraformResourceData) (OrgPolicy, error) {
 	listPolicy, err := expandListOrganizationPolicy(d.Get("list_policy").([]interface{}))
 	if err != nil {
 		return OrgPolicy{}, err
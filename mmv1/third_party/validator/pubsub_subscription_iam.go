@@ -0,0 +1,65 @@
+package google
+
+func GetPubsubSubscriptionIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newPubsubSubscriptionIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetPubsubSubscriptionIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newPubsubSubscriptionIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetPubsubSubscriptionIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newPubsubSubscriptionIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergePubsubSubscriptionIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergePubsubSubscriptionIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergePubsubSubscriptionIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergePubsubSubscriptionIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergePubsubSubscriptionIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newPubsubSubscriptionIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	tmpl, err := pubsubSubscriptionIamAssetNameTmpl(d, config)
+	if err != nil {
+		return []Asset{}, err
+	}
+	return newResourceIamAsset(d, config, expandBindings, tmpl, "pubsub.googleapis.com/Subscription")
+}
+
+func FetchPubsubSubscriptionIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	tmpl, err := pubsubSubscriptionIamAssetNameTmpl(d, config)
+	if err != nil {
+		return Asset{}, err
+	}
+	return fetchIamPolicy(
+		NewPubsubSubscriptionIamUpdater,
+		d,
+		config,
+		tmpl,
+		"pubsub.googleapis.com/Subscription",
+	)
+}
+
+// pubsubSubscriptionIamAssetNameTmpl resolves subscription, which may be in a short form.
+func pubsubSubscriptionIamAssetNameTmpl(d TerraformResourceData, config *Config) (string, error) {
+	return iamUpdaterAssetNameTmpl(d, config, NewPubsubSubscriptionIamUpdater, "pubsub.googleapis.com", "subscription")
+}
@@ -0,0 +1,52 @@
+package google
+
+func GetServiceAccountIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newServiceAccountIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetServiceAccountIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newServiceAccountIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetServiceAccountIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newServiceAccountIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeServiceAccountIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeServiceAccountIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeServiceAccountIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeServiceAccountIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeServiceAccountIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newServiceAccountIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	return newResourceIamAsset(d, config, expandBindings, "//iam.googleapis.com/{{service_account_id}}", "iam.googleapis.com/ServiceAccount")
+}
+
+func FetchServiceAccountIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	return fetchIamPolicy(
+		NewServiceAccountIamUpdater,
+		d,
+		config,
+		"//iam.googleapis.com/{{service_account_id}}",
+		"iam.googleapis.com/ServiceAccount",
+	)
+}
@@ -0,0 +1,52 @@
+package google
+
+func GetSpannerDatabaseIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newSpannerDatabaseIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetSpannerDatabaseIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newSpannerDatabaseIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetSpannerDatabaseIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newSpannerDatabaseIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeSpannerDatabaseIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeSpannerDatabaseIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeSpannerDatabaseIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeSpannerDatabaseIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeSpannerDatabaseIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newSpannerDatabaseIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	return newResourceIamAsset(d, config, expandBindings, "//spanner.googleapis.com/projects/{{project}}/instances/{{instance}}/databases/{{database}}", "spanner.googleapis.com/Database")
+}
+
+func FetchSpannerDatabaseIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	return fetchIamPolicy(
+		NewSpannerDatabaseIamUpdater,
+		d,
+		config,
+		"//spanner.googleapis.com/projects/{{project}}/instances/{{instance}}/databases/{{database}}",
+		"spanner.googleapis.com/Database",
+	)
+}
@@ -0,0 +1,52 @@
+package google
+
+func GetSpannerInstanceIamPolicyCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newSpannerInstanceIamAsset(d, config, expandIamPolicyBindings)
+}
+
+func GetSpannerInstanceIamBindingCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newSpannerInstanceIamAsset(d, config, expandIamRoleBindings)
+}
+
+func GetSpannerInstanceIamMemberCaiObject(d TerraformResourceData, config *Config) ([]Asset, error) {
+	return newSpannerInstanceIamAsset(d, config, expandIamMemberBindings)
+}
+
+func MergeSpannerInstanceIamPolicy(existing, incoming Asset) Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+func MergeSpannerInstanceIamBinding(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAuthoritativeBindings)
+}
+
+func MergeSpannerInstanceIamBindingDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAuthoritativeBindings)
+}
+
+func MergeSpannerInstanceIamMember(existing, incoming Asset) Asset {
+	return mergeIamAssets(existing, incoming, mergeAdditiveBindings)
+}
+
+func MergeSpannerInstanceIamMemberDelete(existing, incoming Asset) Asset {
+	return mergeDeleteIamAssets(existing, incoming, mergeDeleteAdditiveBindings)
+}
+
+func newSpannerInstanceIamAsset(
+	d TerraformResourceData,
+	config *Config,
+	expandBindings func(d TerraformResourceData) ([]IAMBinding, error),
+) ([]Asset, error) {
+	return newResourceIamAsset(d, config, expandBindings, "//spanner.googleapis.com/projects/{{project}}/instances/{{instance}}", "spanner.googleapis.com/Instance")
+}
+
+func FetchSpannerInstanceIamPolicy(d TerraformResourceData, config *Config) (Asset, error) {
+	return fetchIamPolicy(
+		NewSpannerInstanceIamUpdater,
+		d,
+		config,
+		"//spanner.googleapis.com/projects/{{project}}/instances/{{instance}}",
+		"spanner.googleapis.com/Instance",
+	)
+}
@@ -20,13 +20,68 @@ import (
 	"io/ioutil"
 	"path"
 	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/golang/glog"
 )
 
+// generateDocumentation renders the website documentation for every resource
+// across all versions. Resources present at multiple versions share a single
+// page, with beta-only fields marked. Generation fails if any documented
+// property is missing a description.
+func generateDocumentation(resources map[Version][]*Resource) {
+	var docs []ResourceInput
+	seen := make(map[string]bool)
+	for _, version := range allVersions() {
+		for _, res := range resources[version] {
+			if seen[res.TerraformName()] {
+				continue
+			}
+			seen[res.TerraformName()] = true
+
+			merged, betaOnly := mergeResource(res, resources)
+			docs = append(docs, ResourceInput{
+				Resource: *merged,
+				BetaOnly: betaOnly,
+			})
+		}
+	}
+
+	var missing []string
+	for _, doc := range docs {
+		for _, p := range undescribedProperties(doc.Properties) {
+			missing = append(missing, fmt.Sprintf("%s: %s", doc.TerraformName(), p))
+		}
+		for _, p := range undescribedProperties(doc.ReusedTypes) {
+			missing = append(missing, fmt.Sprintf("%s: %s", doc.TerraformName(), p))
+		}
+	}
+	if len(missing) > 0 {
+		glog.Exitf("properties are missing descriptions:\n%s", strings.Join(missing, "\n"))
+	}
+
+	for _, doc := range docs {
+		generateResourceWebsiteFile(doc)
+	}
+}
+
+// undescribedProperties returns the override paths of any properties, or
+// their sub-properties, without a description.
+func undescribedProperties(props []Property) (paths []string) {
+	for _, p := range props {
+		if strings.TrimSpace(p.Description) == "" {
+			paths = append(paths, p.overridePath())
+		}
+		paths = append(paths, undescribedProperties(p.Properties)...)
+	}
+	return paths
+}
+
 // Merges beta and GA resources for doc generation for a particular resource.
-func mergeResource(res *Resource, resources map[Version][]*Resource, version *Version) *Resource {
+// Returns the merged resource, and whether the resource is only available at
+// beta. The input resources are not modified.
+func mergeResource(res *Resource, resources map[Version][]*Resource) (*Resource, bool) {
 	resourceAcrossVersions := make(map[Version]*Resource)
 	for v, resList := range resources {
 		for _, r := range resList {
@@ -40,18 +95,19 @@ func mergeResource(res *Resource, resources map[Version][]*Resource, version *Ve
 	beta, betaExists := resourceAcrossVersions[BETA_VERSION]
 	if gaExists {
 		if betaExists {
-			return mergeResources(ga, beta)
+			return mergeResources(ga, beta), false
 		}
-		return ga
+		return ga, false
 	}
-	beta.Description = "Beta only"
-	return beta
+	return beta, true
 }
 
 func mergeResources(ga, beta *Resource) *Resource {
-	beta.Properties = mergeProperties(ga.Properties, beta.Properties)
+	merged := *beta
+	merged.Properties = mergeProperties(ga.Properties, beta.Properties)
+	merged.ReusedTypes = mergeProperties(ga.ReusedTypes, beta.ReusedTypes)
 
-	return beta
+	return &merged
 }
 
 // Marks any sub properties as beta only
@@ -60,19 +116,9 @@ func mergeProperties(ga, beta []Property) []Property {
 	for _, p := range ga {
 		gaProps[p.title] = p
 	}
-	betaProps := make(map[string]Property)
-	for _, p := range beta {
-		betaProps[p.title] = p
-	}
-	inOrder := make([]string, 0)
-	for k, _ := range betaProps {
-		inOrder = append(inOrder, k)
-	}
-	sort.Strings(inOrder)
-	modifiedProps := make([]Property, 0)
-	for _, name := range inOrder {
-		v := betaProps[name]
-		if gaProp, ok := gaProps[name]; !ok {
+	modifiedProps := make([]Property, 0, len(beta))
+	for _, v := range beta {
+		if gaProp, ok := gaProps[v.title]; !ok {
 			v.Description = fmt.Sprintf("(Beta only) %s", v.Description)
 		} else if len(v.Properties) != 0 {
 			// Look for sub-properties that might be beta only.
@@ -82,16 +128,13 @@ func mergeProperties(ga, beta []Property) []Property {
 		modifiedProps = append(modifiedProps, v)
 	}
 
+	// keep the same order as generated resources
+	sort.SliceStable(modifiedProps, propComparator(modifiedProps))
 	return modifiedProps
 }
 
-func generateResourceWebsiteFile(res *Resource, resources map[Version][]*Resource, version *Version) {
-	
-	res = mergeResource(res, resources, version)
-	// Generate resource website file
-	tmplInput := ResourceInput{
-		Resource: *res,
-	}
+func generateResourceWebsiteFile(tmplInput ResourceInput) {
+	res := tmplInput.Resource
 
 	tmpl, err := template.New("resource.html.markdown.tmpl").Funcs(TemplateFunctions).ParseFiles(
 		"templates/resource.html.markdown.tmpl",
@@ -17,30 +17,14 @@ package main
 import (
 	"fmt"
 	"regexp"
-	"sort"
-	"strings"
 
-	"bitbucket.org/creachadair/stringset"
+	"github.com/GoogleCloudPlatform/magic-modules/tpgtools/naming"
 	"github.com/nasa9084/go-openapi"
 )
 
-const PatternPart = "{{(\\w+)}}"
-
-func idParts(id string) (parts []string) {
-	r := regexp.MustCompile(PatternPart)
-
-	// returns [["{{field}}", "field"] ...]
-	idTmplAndParts := r.FindAllStringSubmatch(id, -1)
-	for _, v := range idTmplAndParts {
-		parts = append(parts, v[1])
-	}
-
-	return parts
-}
-
 // PatternToRegex formats a pattern string into a Python-compatible regex.
 func PatternToRegex(s string) string {
-	re := regexp.MustCompile(PatternPart)
+	re := regexp.MustCompile(naming.PatternPart)
 	return re.ReplaceAllString(s, "(?P<$1>[^/]+)")
 }
 
@@ -63,57 +47,14 @@ func findResourceId(schema *openapi.Schema, overrides Overrides, location string
 	}
 
 	for _, override := range overrides {
-		if override.Type == CustomName {
-			if strings.Contains(id, fmt.Sprintf("{{%s}}", *override.Field)) {
-				id = strings.Replace(id, fmt.Sprintf("{{%s}}", *override.Field), fmt.Sprintf("{{%s}}", override.Details.(map[interface{}]interface{})["name"].(string)), 1)
-			}
-		}
-	}
-	return id, nil
-}
-
-// Finds all import formats for a given id. This can include short forms and
-// partial forms with inferred project/region/etc
-func defaultImportFormats(id string) (formats []string) {
-	uniqueFormats := stringset.New()
-
-	uniqueFormats.Add(id)
-
-	parts := idParts(id)
-	for i, v := range parts {
-		parts[i] = fmt.Sprintf("{{%s}}", v)
-	}
-
-	// short form "{{project}}/{{region}}/{{name}}"
-	uniqueFormats[strings.Join(parts, "/")] = struct{}{}
-
-	// short form sans project
-	var locationalParts []string
-	for _, v := range parts {
-		if v != "{{project}}" {
-			locationalParts = append(locationalParts, v)
+		if override.Type != CustomName || override.Field == nil || !compareLocation(override.Location, location) {
+			continue
 		}
-	}
-	if len(locationalParts) != 0 {
-		uniqueFormats.Add(strings.Join(locationalParts, "/"))
-	}
-
-	// short form sans project, region, zone
-	var resourceParts []string
-	for _, v := range locationalParts {
-		if v != "{{zone}}" && v != "{{region}}" {
-			resourceParts = append(resourceParts, v)
+		cn := CustomNameDetails{}
+		if err := convert(override.Details, &cn); err != nil {
+			return "", fmt.Errorf("failed to decode custom name details: %v", err)
 		}
+		id = naming.RenameIDField(id, *override.Field, cn.Name)
 	}
-	if len(resourceParts) != 0 {
-		uniqueFormats.Add(strings.Join(resourceParts, "/"))
-	}
-
-	for _, f := range uniqueFormats.Elements() {
-		formats = append(formats, f)
-	}
-
-	// formats must be ordered most to least specific
-	sort.SliceStable(formats, formatComparator(formats))
-	return formats
+	return id, nil
 }
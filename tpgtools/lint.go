@@ -0,0 +1,380 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+// 
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tpgtools/naming"
+	"github.com/golang/glog"
+	"github.com/nasa9084/go-openapi"
+	"gopkg.in/yaml.v2"
+)
+
+// overrideDetails maps each OverrideType to a constructor for its typed
+// Details struct. Override types that take no details map to nil.
+var overrideDetails = map[OverrideType]func() interface{}{
+	// Resource-level Overrides
+	VirtualField:          func() interface{} { return &VirtualFieldDetails{} },
+	CustomID:              func() interface{} { return &CustomIDDetails{} },
+	CustomizeDiff:         func() interface{} { return &CustomizeDiffDetails{} },
+	ImportFormat:          func() interface{} { return &ImportFormatDetails{} },
+	Mutex:                 func() interface{} { return &MutexDetails{} },
+	PreCreate:             func() interface{} { return &PreCreateFunctionDetails{} },
+	PostCreate:            func() interface{} { return &PostCreateFunctionDetails{} },
+	PreDelete:             func() interface{} { return &PreDeleteFunctionDetails{} },
+	CustomResourceName:    func() interface{} { return &CustomResourceNameDetails{} },
+	NoSweeper:             nil,
+	CustomImport:          func() interface{} { return &CustomImportFunctionDetails{} },
+	CustomCreateDirective: func() interface{} { return &CustomCreateDirectiveDetails{} },
+	SkipDelete:            func() interface{} { return &SkipDeleteFunctionDetails{} },
+	GenerateDatasource:    nil,
+
+	// Field-level Overrides
+	CustomConfigMode:     func() interface{} { return &CustomConfigModeDetails{} },
+	CustomDescription:    func() interface{} { return &CustomDescriptionDetails{} },
+	NamePrefix:           nil,
+	CustomName:           func() interface{} { return &CustomNameDetails{} },
+	CustomStateGetter:    func() interface{} { return &CustomStateGetterDetails{} },
+	CustomStateSetter:    func() interface{} { return &CustomStateSetterDetails{} },
+	CustomValidation:     func() interface{} { return &CustomValidationDetails{} },
+	Deprecated:           func() interface{} { return &DeprecatedDetails{} },
+	DiffSuppressFunc:     func() interface{} { return &CustomDiffSuppressFuncDetails{} },
+	Exclude:              nil,
+	CustomIdentityGetter: func() interface{} { return &CustomIdentityGetterDetails{} },
+	Removed:              func() interface{} { return &RemovedDetails{} },
+	SetHashFunc:          func() interface{} { return &SetHashFuncDetails{} },
+	CollapsedObject:      nil,
+	IgnoreRead:           nil,
+	GenerateIfNotSet:     nil,
+}
+
+// fieldOverrides is the set of OverrideTypes that must specify a Field. All
+// other types apply to the resource and must not.
+var fieldOverrides = map[OverrideType]bool{
+	CustomConfigMode:     true,
+	CustomDescription:    true,
+	NamePrefix:           true,
+	CustomName:           true,
+	CustomStateGetter:    true,
+	CustomStateSetter:    true,
+	CustomValidation:     true,
+	Deprecated:           true,
+	DiffSuppressFunc:     true,
+	Exclude:              true,
+	CustomIdentityGetter: true,
+	Removed:              true,
+	SetHashFunc:          true,
+	CollapsedObject:      true,
+	IgnoreRead:           true,
+	GenerateIfNotSet:     true,
+}
+
+// lintProblem is a single problem found in an override file.
+type lintProblem struct {
+	file    string
+	line    int
+	message string
+}
+
+func (p lintProblem) String() string {
+	if p.line == 0 {
+		return fmt.Sprintf("%s: %s", p.file, p.message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.file, p.line, p.message)
+}
+
+// lintOverrides checks every override file under the overrides path against
+// the typed override details and the modeled resource for its spec, and exits
+// non-zero after reporting all problems found. Override files without a spec
+// under the spec path are skipped.
+func lintOverrides() {
+	if fPath == nil || *fPath == "" {
+		glog.Exit("No path specified")
+	}
+	if tPath == nil || *tPath == "" {
+		glog.Exit("No overrides path specified")
+	}
+
+	var problems []lintProblem
+	err := filepath.Walk(*tPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(p) != ".yaml" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(*tPath, p)
+		if err != nil {
+			return err
+		}
+
+		// if a filter is specified, skip filtered services and resources
+		if sFilter != nil && *sFilter != "" && *sFilter != strings.Split(rel, string(filepath.Separator))[0] {
+			return nil
+		}
+		if rFilter != nil && *rFilter != "" && strings.ToLower(*rFilter) != strings.ToLower(stripExt(info.Name())) {
+			return nil
+		}
+
+		if _, err := os.Stat(filepath.Join(*fPath, rel)); os.IsNotExist(err) {
+			glog.Infof("skipping %s, which has no spec in %s", p, *fPath)
+			return nil
+		}
+
+		problems = append(problems, lintOverrideFile(p, rel)...)
+		return nil
+	})
+	if err != nil {
+		glog.Exit(err)
+	}
+
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		glog.Exitf("found %d problem(s) in override files", len(problems))
+	}
+}
+
+// lintOverrideFile returns the problems found in the override file at p. rel
+// is the path of the file relative to the overrides path, which matches the
+// path of its spec relative to the spec path.
+func lintOverrideFile(p, rel string) (problems []lintProblem) {
+	problem := func(line int, format string, a ...interface{}) {
+		problems = append(problems, lintProblem{file: p, line: line, message: fmt.Sprintf(format, a...)})
+	}
+
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		problem(0, "%v", err)
+		return problems
+	}
+
+	overrides := Overrides{}
+	if err := yaml.UnmarshalStrict(b, &overrides); err != nil {
+		problem(0, "%v", err)
+		return problems
+	}
+	lines := overrideLines(b)
+	lineOf := func(i int) int {
+		if i < len(lines) {
+			return lines[i]
+		}
+		return 0
+	}
+
+	packagePath := filepath.ToSlash(filepath.Dir(rel))
+	document, schema, err := loadDocument(filepath.Join(*fPath, rel))
+	if err != nil {
+		problem(0, "failed to load spec for overrides: %v", err)
+		return problems
+	}
+	locations := schemaLocations(schema)
+
+	seen := make(map[string]int)
+	for i, o := range overrides {
+		line := lineOf(i)
+
+		newDetails, ok := overrideDetails[o.Type]
+		if !ok {
+			problem(line, "unknown override type %q", o.Type)
+			continue
+		}
+
+		if fieldOverrides[o.Type] && o.Field == nil {
+			problem(line, "override type %v requires a field", o.Type)
+		} else if !fieldOverrides[o.Type] && o.Field != nil {
+			problem(line, "override type %v applies to the resource and cannot specify a field", o.Type)
+		}
+
+		if o.Location != nil && !stringInSlice(*o.Location, locations) {
+			problem(line, "location %q matches none of the resource's locations %q", *o.Location, locations)
+		}
+
+		if newDetails == nil {
+			if o.Details != nil {
+				problem(line, "override type %v does not take details", o.Type)
+			}
+		} else if o.Details == nil {
+			problem(line, "override type %v requires details", o.Type)
+		} else if err := convertStrict(o.Details, newDetails()); err != nil {
+			problem(line, "invalid details for override type %v: %v", o.Type, err)
+		}
+
+		// VIRTUAL_FIELD may be repeated, as each adds a separate field.
+		if o.Type != VirtualField {
+			key := fmt.Sprintf("%v/%s/%s", o.Type, stringOrEmpty(o.Field), stringOrEmpty(o.Location))
+			if first, ok := seen[key]; ok {
+				problem(line, "duplicate override of type %v, first defined on line %d", o.Type, first)
+			} else {
+				seen[key] = line
+			}
+		}
+	}
+
+	typeFetcher := NewTypeFetcher(document)
+	specFields := schemaOverridePaths(schema, typeFetcher, "")
+
+	// Modeling the resource fails hard on malformed overrides, so fields are
+	// only resolved against the modeled resource once the overrides themselves
+	// are known to be valid, and against the spec otherwise.
+	if len(problems) > 0 {
+		for i, o := range overrides {
+			if o.Field != nil && !specFields[*o.Field] {
+				problem(lineOf(i), "field %q does not exist in the spec", *o.Field)
+			}
+		}
+		return problems
+	}
+
+	fieldsByLocation := make(map[string]map[string]bool)
+	for _, l := range locations {
+		res, err := createResource(schema, typeFetcher, overrides, packagePath, l)
+		if err != nil {
+			problem(0, "failed to model resource for location %q: %v", l, err)
+			continue
+		}
+		fieldsByLocation[l] = propertyOverridePaths(res.Properties)
+	}
+
+	for i, o := range overrides {
+		if o.Field == nil {
+			continue
+		}
+
+		// Excluded fields are absent from the modeled resource, so are resolved
+		// against the spec instead.
+		if o.Type == Exclude {
+			if !specFields[*o.Field] {
+				problem(lineOf(i), "field %q does not exist in the spec", *o.Field)
+			}
+			continue
+		}
+
+		found := false
+		for l, fields := range fieldsByLocation {
+			if compareLocation(o.Location, l) && fields[*o.Field] {
+				found = true
+			}
+		}
+		if !found {
+			if specFields[*o.Field] {
+				problem(lineOf(i), "field %q is excluded from the resource", *o.Field)
+			} else {
+				problem(lineOf(i), "field %q does not exist in the resource", *o.Field)
+			}
+		}
+	}
+
+	return problems
+}
+
+// overrideLines returns the line number each top-level entry of an override
+// file starts on.
+func overrideLines(b []byte) (lines []int) {
+	r := regexp.MustCompile(`^-(\s|$)`)
+	for i, l := range strings.Split(string(b), "\n") {
+		if r.MatchString(l) {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// propertyOverridePaths returns the set of override paths of props and all of
+// their sub-properties.
+func propertyOverridePaths(props []Property) map[string]bool {
+	paths := make(map[string]bool)
+	for _, p := range props {
+		paths[p.overridePath()] = true
+		for k := range propertyOverridePaths(p.Properties) {
+			paths[k] = true
+		}
+	}
+	return paths
+}
+
+// schemaOverridePaths returns the set of override paths of all the properties
+// in an OpenAPI schema, prefixed with the override path of its parent.
+func schemaOverridePaths(schema *openapi.Schema, typeFetcher *TypeFetcher, parent string) map[string]bool {
+	paths := make(map[string]bool)
+	for k, v := range schema.Properties {
+		if v.Ref != "" {
+			resolved, err := typeFetcher.ResolveSchema(v.Ref)
+			if err != nil {
+				continue
+			}
+			v = resolved
+		}
+
+		p := naming.JSONToSnakeCase(k)
+		if parent != "" {
+			p = parent + "." + p
+		}
+		paths[p] = true
+
+		sub := v
+		if v.Items != nil {
+			sub = v.Items
+			if sub.Ref != "" {
+				resolved, err := typeFetcher.ResolveSchema(sub.Ref)
+				if err != nil {
+					continue
+				}
+				sub = resolved
+			}
+		}
+		for k := range schemaOverridePaths(sub, typeFetcher, p) {
+			paths[k] = true
+		}
+	}
+	return paths
+}
+
+// convertStrict is convert, but fails if item contains fields not present in
+// out.
+func convertStrict(item, out interface{}) error {
+	bytes, err := yaml.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %v", err)
+	}
+
+	err = yaml.UnmarshalStrict(bytes, out)
+	if te, ok := err.(*yaml.TypeError); ok {
+		// Line numbers refer to the re-marshalled details rather than the
+		// override file, so drop them.
+		r := regexp.MustCompile(`^line \d+: `)
+		var msgs []string
+		for _, e := range te.Errors {
+			msgs = append(msgs, r.ReplaceAllString(e, ""))
+		}
+		return fmt.Errorf("%s", strings.Join(msgs, "; "))
+	}
+	return err
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -40,11 +40,24 @@ var sFilter = flag.String("service", "", "optional service name. If specified, o
 var rFilter = flag.String("resource", "", "optional resource name (from filename). If specified, only resources with this name are generated")
 var vFilter = flag.String("version", "", "optional version name. If specified, this version is preferred for resource generation when applicable")
 
-var mode = flag.String("mode", "", "mode for the generator. If unset, creates the provider. Options: 'serialization'")
+var mode = flag.String("mode", "", "mode for the generator. If unset, creates the provider. Options: 'serialization', 'docs', 'lint', 'versiondiff', 'snapshot', 'breakingchanges'. 'snapshot' snapshots every version to the output path, or only the given version")
+
+var oldSnapshotPath = flag.String("old_snapshot", "", "path to the snapshot of the previous generation, for 'breakingchanges' mode")
+var newSnapshotPath = flag.String("new_snapshot", "", "path to the snapshot of the current generation, for 'breakingchanges' mode. Renamed fields are reported as removed and added, with a hint when the rename can be inferred")
 
 var terraformResourceDirectory = "google-beta"
 
 func main() {
+	flag.Parse()
+	if mode != nil && *mode == "lint" {
+		lintOverrides()
+		return
+	}
+	if mode != nil && *mode == "breakingchanges" {
+		compareSnapshots(*oldSnapshotPath, *newSnapshotPath)
+		return
+	}
+
 	resources := loadAndModelResources()
 	var resourcesForVersion []*Resource
 	var version *Version
@@ -56,10 +69,21 @@ func main() {
 		resourcesForVersion = resources[*version]
 	} else {
 		resourcesForVersion = resources[allVersions()[0]]
+		version = &allVersions()[0]
 	}
 
 	if mode != nil && *mode == "serialization" {
 		generateSerializationLogic(resourcesForVersion)
+	} else if mode != nil && *mode == "snapshot" {
+		if vFilter != nil && *vFilter != "" {
+			generateSnapshot(*version, resourcesForVersion)
+		} else {
+			generateSnapshots(resources)
+		}
+	} else if mode != nil && *mode == "versiondiff" {
+		generateVersionDiff(resources)
+	} else if mode != nil && *mode == "docs" {
+		generateDocumentation(resources)
 	} else {
 		for _, resource := range resourcesForVersion {
 			resJSON, err := json.MarshalIndent(resource, "", "  ")
@@ -70,8 +94,8 @@ func main() {
 			}
 			generateResourceFile(resource)
 			generateSweeperFile(resource)
-			// Disabled to allow handwriting files until samples exist
-			// generateResourceWebsiteFile(resource, resources, version)
+			generateDatasourceFile(resource)
+			generateUnitTestFile(resource)
 		}
 
 		if oPath == nil || *oPath == "" {
@@ -133,15 +157,7 @@ func loadAndModelResources() map[Version][]*Resource {
 					continue
 				}
 
-				// TODO: use yaml.UnmarshalStrict once apply / list paths are changed to
-				// specification extensions and we're using a datatype that supports them.
-				document := &openapi.Document{}
-				p := path.Join(*fPath, packagePath, f.Name())
-				b, err := ioutil.ReadFile(p)
-				if err != nil {
-					glog.Exit(err)
-				}
-				err = yaml.Unmarshal(b, document)
+				document, schema, err := loadDocument(path.Join(*fPath, packagePath, f.Name()))
 				if err != nil {
 					glog.Exit(err)
 				}
@@ -162,38 +178,8 @@ func loadAndModelResources() map[Version][]*Resource {
 					}
 				}
 
-				titleParts := strings.Split(document.Info.Title, "/")
-
-				var schema *openapi.Schema
-				for k, v := range document.Components.Schemas {
-					if k == titleParts[len(titleParts)-1] {
-						schema = v
-						schema.Title = k
-					}
-				}
-
-				if schema == nil {
-					glog.Exit(fmt.Sprintf("Could not find document schema for %s", document.Info.Title))
-				}
-
-				if err := schema.Validate(); err != nil {
-					glog.Exit(err)
-				}
-
-				lRaw := schema.Extension["x-dcl-locations"].([]interface{})
-
 				typeFetcher := NewTypeFetcher(document)
-				var locations []string
-				// If the schema cannot be split into two or mor locations, we specify this
-				// by passing a single empty location string.
-				if len(lRaw) < 2 {
-					locations = make([]string, 1)
-				} else {
-					locations = make([]string, 0, len(lRaw))
-					for _, l := range lRaw {
-						locations = append(locations, l.(string))
-					}
-				}
+				locations := schemaLocations(schema)
 
 				for _, l := range locations {
 					res, err := createResource(schema, typeFetcher, overrides, packagePath, l)
@@ -210,6 +196,59 @@ func loadAndModelResources() map[Version][]*Resource {
 	return resources
 }
 
+// loadDocument reads the OpenAPI document at p and returns it along with the
+// schema of the resource it describes.
+func loadDocument(p string) (*openapi.Document, *openapi.Schema, error) {
+	// TODO: use yaml.UnmarshalStrict once apply / list paths are changed to
+	// specification extensions and we're using a datatype that supports them.
+	document := &openapi.Document{}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = yaml.Unmarshal(b, document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	titleParts := strings.Split(document.Info.Title, "/")
+
+	var schema *openapi.Schema
+	for k, v := range document.Components.Schemas {
+		if k == titleParts[len(titleParts)-1] {
+			schema = v
+			schema.Title = k
+		}
+	}
+
+	if schema == nil {
+		return nil, nil, fmt.Errorf("Could not find document schema for %s", document.Info.Title)
+	}
+
+	if err := schema.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return document, schema, nil
+}
+
+// schemaLocations returns the locations a resource schema is split into.
+func schemaLocations(schema *openapi.Schema) []string {
+	lRaw := schema.Extension["x-dcl-locations"].([]interface{})
+
+	// If the schema cannot be split into two or mor locations, we specify this
+	// by passing a single empty location string.
+	if len(lRaw) < 2 {
+		return make([]string, 1)
+	}
+
+	locations := make([]string, 0, len(lRaw))
+	for _, l := range lRaw {
+		locations = append(locations, l.(string))
+	}
+	return locations
+}
+
 func generateSerializationLogic(specs []*Resource) {
 	buf := bytes.Buffer{}
 	tmpl, err := template.New("serialization.go.tmpl").Funcs(TemplateFunctions).ParseFiles(
@@ -319,6 +358,78 @@ func generateSweeperFile(res *Resource) {
 	}
 }
 
+func generateDatasourceFile(res *Resource) {
+	if !res.HasDatasource {
+		return
+	}
+
+	// Generate datasource file
+	tmplInput := ResourceInput{
+		Resource: *res,
+	}
+
+	tmpl, err := template.New("datasource.go.tmpl").Funcs(TemplateFunctions).ParseFiles(
+		"templates/datasource.go.tmpl",
+	)
+	if err != nil {
+		glog.Exit(err)
+	}
+
+	contents := bytes.Buffer{}
+	if err = tmpl.ExecuteTemplate(&contents, "datasource.go.tmpl", tmplInput); err != nil {
+		glog.Exit(err)
+	}
+
+	formatted, err := formatSource(&contents)
+	if err != nil {
+		glog.Error(fmt.Errorf("error formatting %v: %v", res.Package+res.Name(), err))
+	}
+
+	if oPath == nil || *oPath == "" {
+		fmt.Printf("%v", string(formatted))
+	} else {
+		outname := fmt.Sprintf("data_source_%s_%s.go", res.Package, res.Name())
+		err := ioutil.WriteFile(path.Join(*oPath, terraformResourceDirectory, outname), formatted, 0644)
+		if err != nil {
+			glog.Exit(err)
+		}
+	}
+}
+
+func generateUnitTestFile(res *Resource) {
+	// Generate unit test file
+	tmplInput := ResourceInput{
+		Resource: *res,
+	}
+
+	tmpl, err := template.New("unit_test.go.tmpl").Funcs(TemplateFunctions).ParseFiles(
+		"templates/unit_test.go.tmpl",
+	)
+	if err != nil {
+		glog.Exit(err)
+	}
+
+	contents := bytes.Buffer{}
+	if err = tmpl.ExecuteTemplate(&contents, "unit_test.go.tmpl", tmplInput); err != nil {
+		glog.Exit(err)
+	}
+
+	formatted, err := formatSource(&contents)
+	if err != nil {
+		glog.Error(fmt.Errorf("error formatting %v: %v", res.Package+res.Name(), err))
+	}
+
+	if oPath == nil || *oPath == "" {
+		fmt.Printf("%v", string(formatted))
+	} else {
+		outname := fmt.Sprintf("resource_%s_%s_generated_unit_test.go", res.Package, res.Name())
+		err := ioutil.WriteFile(path.Join(*oPath, terraformResourceDirectory, outname), formatted, 0644)
+		if err != nil {
+			glog.Exit(err)
+		}
+	}
+}
+
 var TemplateFunctions = template.FuncMap{
 	"title":          strings.Title,
 	"patternToRegex": PatternToRegex,
@@ -24,28 +24,6 @@ import (
 	"github.com/kylelemons/godebug/pretty"
 )
 
-// Sort id formats based on the order they should be matched. This is
-// most specific first, so {{project}}/{{region}}/{{name}} would be applied
-// before {{region}}/{{name}}
-func formatComparator(formats []string) func(i, j int) bool {
-	return func(i, j int) bool {
-		l := formats[i]
-		r := formats[j]
-
-		lBrace := strings.Count(l, "{{")
-		rBrace := strings.Count(r, "{{")
-
-		lSlash := strings.Count(l, "/")
-		rSlash := strings.Count(r, "/")
-
-		if lBrace == rBrace {
-			return lSlash > rSlash // > and not <, we want more to appear first
-		}
-
-		return lBrace > rBrace
-	}
-}
-
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
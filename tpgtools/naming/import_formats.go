@@ -0,0 +1,111 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+// 
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PatternPart matches a {{field}} in an id pattern.
+const PatternPart = "{{(\\w+)}}"
+
+// IDParts returns the names of the fields in an id pattern, in order.
+func IDParts(id string) (parts []string) {
+	r := regexp.MustCompile(PatternPart)
+
+	// returns [["{{field}}", "field"] ...]
+	idTmplAndParts := r.FindAllStringSubmatch(id, -1)
+	for _, v := range idTmplAndParts {
+		parts = append(parts, v[1])
+	}
+
+	return parts
+}
+
+// RenameIDField renames the {{field}} in an id pattern to {{name}}, as a
+// CUSTOM_NAME override of the field does.
+func RenameIDField(id, field, name string) string {
+	return strings.Replace(id, fmt.Sprintf("{{%s}}", field), fmt.Sprintf("{{%s}}", name), 1)
+}
+
+// DefaultImportFormats finds all import formats for a given id. This can
+// include short forms and partial forms with inferred project/region/etc
+func DefaultImportFormats(id string) (formats []string) {
+	uniqueFormats := map[string]bool{id: true}
+
+	parts := IDParts(id)
+	for i, v := range parts {
+		parts[i] = fmt.Sprintf("{{%s}}", v)
+	}
+
+	// short form "{{project}}/{{region}}/{{name}}"
+	uniqueFormats[strings.Join(parts, "/")] = true
+
+	// short form sans project
+	var locationalParts []string
+	for _, v := range parts {
+		if v != "{{project}}" {
+			locationalParts = append(locationalParts, v)
+		}
+	}
+	if len(locationalParts) != 0 {
+		uniqueFormats[strings.Join(locationalParts, "/")] = true
+	}
+
+	// short form sans project, region, zone
+	var resourceParts []string
+	for _, v := range locationalParts {
+		if v != "{{zone}}" && v != "{{region}}" {
+			resourceParts = append(resourceParts, v)
+		}
+	}
+	if len(resourceParts) != 0 {
+		uniqueFormats[strings.Join(resourceParts, "/")] = true
+	}
+
+	for f := range uniqueFormats {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+
+	// formats must be ordered most to least specific
+	sort.SliceStable(formats, FormatComparator(formats))
+	return formats
+}
+
+// FormatComparator sorts id formats based on the order they should be
+// matched. This is most specific first, so {{project}}/{{region}}/{{name}}
+// would be applied before {{region}}/{{name}}
+func FormatComparator(formats []string) func(i, j int) bool {
+	return func(i, j int) bool {
+		l := formats[i]
+		r := formats[j]
+
+		lBrace := strings.Count(l, "{{")
+		rBrace := strings.Count(r, "{{")
+
+		lSlash := strings.Count(l, "/")
+		rSlash := strings.Count(r, "/")
+
+		if lBrace == rBrace {
+			return lSlash > rSlash // > and not <, we want more to appear first
+		}
+
+		return lBrace > rBrace
+	}
+}
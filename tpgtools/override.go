@@ -40,6 +40,7 @@ const (
 	CustomImport                       = "CUSTOM_IMPORT_FUNCTION"
 	CustomCreateDirective              = "CUSTOM_CREATE_DIRECTIVE_FUNCTION"
 	SkipDelete                         = "SKIP_DELETE_FUNCTION"
+	GenerateDatasource                 = "GENERATE_DATASOURCE"
 )
 
 // Field-level Overrides
@@ -31,6 +31,8 @@ type VirtualFieldDetails struct {
 	Type string
 	// If set to true, the field is an output-only field.
 	Output bool
+	// Formatted CommonMark description for the field.
+	Description string
 }
 
 type PreCreateFunctionDetails struct {
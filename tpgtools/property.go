@@ -20,6 +20,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/GoogleCloudPlatform/magic-modules/tpgtools/naming"
 	"github.com/golang/glog"
 	"github.com/nasa9084/go-openapi"
 )
@@ -386,6 +387,75 @@ func (p Property) DefaultSetHashFunc() *string {
 	return nil
 }
 
+// RoundTrippable returns true if a top-level property can be set in state from
+// a DCL object and read back without depending on the provider configuration
+// or other fields.
+func (p Property) RoundTrippable() bool {
+	if !p.Settable || p.StateGetter == nil || p.StateSetter == nil || p.IdentityGetter != nil {
+		return false
+	}
+	if p.Collapsed {
+		return true
+	}
+	// Custom getters and setters may depend on other fields or API calls.
+	return *p.StateGetter == p.DefaultStateGetter() && *p.StateSetter == p.DefaultStateSetter()
+}
+
+// SampleValue returns a snippet of code producing a fully-populated DCL value
+// for the property, for use in generated tests.
+func (p Property) SampleValue() string {
+	switch p.Type.String() {
+	case SchemaTypeBool:
+		return "dcl.Bool(true)"
+	case SchemaTypeString:
+		if p.Type.IsEnum() {
+			return fmt.Sprintf("%s.%s%sEnumRef(%q)", p.resource.Package, p.resource.Type(), p.PackagePath(), p.typ.Enum[0])
+		}
+		return fmt.Sprintf("dcl.String(%q)", p.Name()+"-value")
+	case SchemaTypeInt:
+		return "dcl.Int64(42)"
+	case SchemaTypeFloat:
+		return "dcl.Float64(4.2)"
+	case SchemaTypeMap:
+		return `map[string]string{"key": "value"}`
+	case SchemaTypeList, SchemaTypeSet:
+		if p.Type.IsEnumArray() {
+			enumType := fmt.Sprintf("%s.%s%sEnum", p.resource.Package, p.resource.Type(), p.PackagePath())
+			return fmt.Sprintf("[]%s{%s(%q)}", enumType, enumType, p.typ.Items.Enum[0])
+		}
+		if p.Type.typ.Items != nil && p.Type.typ.Items.Type == "string" {
+			return fmt.Sprintf("[]string{%q}", p.Name()+"-value")
+		}
+
+		if p.Type.typ.Items != nil && len(p.Properties) > 0 {
+			return fmt.Sprintf("[]%s{%s}", p.sampleTypeName(), p.sampleObject())
+		}
+	}
+
+	if p.typ.Type == "object" {
+		return "&" + p.sampleObject()
+	}
+
+	return "nil"
+}
+
+// sampleTypeName is the qualified name of the DCL type of an object property.
+func (p Property) sampleTypeName() string {
+	return fmt.Sprintf("%s.%s%s", p.resource.Package, p.resource.Type(), p.PackagePath())
+}
+
+// sampleObject returns a composite literal of an object property's DCL type
+// with every settable sub-property populated.
+func (p Property) sampleObject() string {
+	var fields []string
+	for _, v := range p.Properties {
+		if v.Settable {
+			fields = append(fields, fmt.Sprintf("%s: %s,", v.PackageName, v.SampleValue()))
+		}
+	}
+	return fmt.Sprintf("%s{\n%s\n}", p.sampleTypeName(), strings.Join(fields, "\n"))
+}
+
 // Objects returns a flatmap of the sub-properties within a Property which are
 // objects (eg: have sub-properties themselves).
 func (p Property) Objects() (props []Property) {
@@ -430,7 +500,7 @@ func (c ConflictsWith) String() string {
 func createPropertiesFromSchema(schema *openapi.Schema, typeFetcher *TypeFetcher, overrides Overrides, resource *Resource, parent *Property, location string) (props []Property, err error) {
 	identityFields := []string{} // always empty if parent != nil
 	if parent == nil {
-		identityFields = idParts(resource.ID)
+		identityFields = naming.IDParts(resource.ID)
 	}
 	for k, v := range schema.Properties {
 		ref := ""
@@ -453,7 +523,7 @@ func createPropertiesFromSchema(schema *openapi.Schema, typeFetcher *TypeFetcher
 		v.Title = k
 
 		p := Property{
-			title:       jsonToSnakeCase(v.Title),
+			title:       naming.JSONToSnakeCase(v.Title),
 			Type:        Type{typ: v},
 			PackageName: packageName,
 			Description: v.Description,
@@ -757,13 +827,14 @@ func createPropertiesFromSchema(schema *openapi.Schema, typeFetcher *TypeFetcher
 
 			// plus, add the "name_prefix" property.
 			props = append(props, Property{
-				title:    "name_prefix",
-				Type:     p.Type,
-				resource: resource,
-				parent:   parent,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				title:       "name_prefix",
+				Type:        p.Type,
+				resource:    resource,
+				parent:      parent,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("Creates a unique name beginning with the specified prefix. Conflicts with `%s`.", p.Name()),
 			})
 		}
 
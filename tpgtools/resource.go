@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"bitbucket.org/creachadair/stringset"
+	"github.com/GoogleCloudPlatform/magic-modules/tpgtools/naming"
 	"github.com/nasa9084/go-openapi"
 )
 
@@ -102,6 +103,9 @@ type Resource struct {
 	// HasSweeper says if this resource has a generated sweeper.
 	HasSweeper bool
 
+	// HasDatasource says if this resource has a generated datasource.
+	HasDatasource bool
+
 	// These are all of the reused types.
 	ReusedTypes []Property
 
@@ -234,6 +238,28 @@ func (r Resource) EnumArrays() (props []Property) {
 	return props
 }
 
+// RoundTripProperties are the top-level properties exercised by the resource's
+// generated unit test.
+func (r Resource) RoundTripProperties() (props []Property) {
+	for _, v := range r.Properties {
+		if v.RoundTrippable() {
+			props = append(props, v)
+		}
+	}
+	return props
+}
+
+// RoundTripUsesDCL returns true if the sample values in the resource's
+// generated unit test use the dcl package.
+func (r Resource) RoundTripUsesDCL() bool {
+	for _, v := range r.RoundTripProperties() {
+		if strings.Contains(v.SampleValue(), "dcl.") {
+			return true
+		}
+	}
+	return false
+}
+
 // AdditionalGoPackages returns a sorted list of additional Go packages to import.
 func (r Resource) AdditionalFileImports() []string {
 	sl := make([]string, 0, len(r.additionalFileImportSet))
@@ -247,7 +273,7 @@ func (r Resource) AdditionalFileImports() []string {
 // If this resource has a server generated field that is used to read the
 // resource. This must be set during create
 func (r Resource) HasServerGeneratedName() bool {
-	identityFields := idParts(r.ID)
+	identityFields := naming.IDParts(r.ID)
 	for _, p := range r.Properties {
 		if stringInSlice(p.Name(), identityFields) {
 			if !p.Settable {
@@ -258,6 +284,31 @@ func (r Resource) HasServerGeneratedName() bool {
 	return false
 }
 
+// DatasourceRequiredFields returns the names of the identity fields that must
+// be specified to read the resource as a datasource.
+func (r Resource) DatasourceRequiredFields() (fields []string) {
+	identityFields := naming.IDParts(r.ID)
+	for _, p := range r.SchemaProperties() {
+		if stringInSlice(p.Name(), identityFields) && p.IdentityGetter == nil {
+			fields = append(fields, p.Name())
+		}
+	}
+	return fields
+}
+
+// DatasourceOptionalFields returns the names of the identity fields that may be
+// specified to read the resource as a datasource, such as project, which can
+// be inferred from the provider.
+func (r Resource) DatasourceOptionalFields() (fields []string) {
+	identityFields := naming.IDParts(r.ID)
+	for _, p := range r.SchemaProperties() {
+		if stringInSlice(p.Name(), identityFields) && p.IdentityGetter != nil {
+			fields = append(fields, p.Name())
+		}
+	}
+	return fields
+}
+
 // SweeperName returns the name of the Sweeper for this resource.
 func (r Resource) SweeperName() string {
 	return strings.Title(r.Package) + strings.Title(r.Name())
@@ -277,6 +328,18 @@ func (r Resource) SweeperFunctionArgs() string {
 	}
 }
 
+// SweeperCreateTimeField returns the name of the field of the DCL type that
+// holds the creation time of the resource, used by sweepers to skip recently
+// created resources, or "" if there is none.
+func (r Resource) SweeperCreateTimeField() string {
+	for _, p := range r.Properties {
+		if p.Name() == "create_time" && p.Type.String() == SchemaTypeString {
+			return p.PackageName
+		}
+	}
+	return ""
+}
+
 // Returns the name of the ID function for the Terraform resource.
 func (r Resource) IdFunction() string {
 	for _, p := range r.Properties {
@@ -290,6 +353,10 @@ func (r Resource) IdFunction() string {
 // ResourceInput is a Resource along with additional generation metadata.
 type ResourceInput struct {
 	Resource
+
+	// BetaOnly is set when documenting a resource that is only available at
+	// the beta version.
+	BetaOnly bool
 }
 
 // RegisterReusedType adds a new reused type if the type does not already exist.
@@ -319,8 +386,8 @@ func createResource(schema *openapi.Schema, typeFetcher *TypeFetcher, overrides
 		resourceTitle = location + resourceTitle
 	}
 	res := Resource{
-		title:                jsonToSnakeCase(resourceTitle),
-		dclname:              jsonToSnakeCase(schema.Title),
+		title:                naming.JSONToSnakeCase(resourceTitle),
+		dclname:              naming.JSONToSnakeCase(schema.Title),
 		Package:              pkg,
 		DCLPackagePath:       packagePath,
 		Description:          schema.Description,
@@ -336,7 +403,7 @@ func createResource(schema *openapi.Schema, typeFetcher *TypeFetcher, overrides
 	}
 
 	if crnameOk {
-		res.title = jsonToSnakeCase(crname.Title)
+		res.title = naming.JSONToSnakeCase(crname.Title)
 	}
 
 	id, err := findResourceId(schema, overrides, location)
@@ -364,7 +431,7 @@ func createResource(schema *openapi.Schema, typeFetcher *TypeFetcher, overrides
 	if ifdOk {
 		res.ImportFormats = ifd.Formats
 	} else {
-		res.ImportFormats = defaultImportFormats(res.ID)
+		res.ImportFormats = naming.DefaultImportFormats(res.ID)
 	}
 
 	// Resource Override: Mutex
@@ -448,6 +515,11 @@ func createResource(schema *openapi.Schema, typeFetcher *TypeFetcher, overrides
 		res.HasSweeper = false
 	}
 
+	// Resource Override: Generate Datasource
+	if overrides.ResourceOverride(GenerateDatasource, location) {
+		res.HasDatasource = true
+	}
+
 	stateHint, ok := schema.Extension["x-dcl-uses-state-hint"].(bool)
 	if ok {
 		res.StateHint = stateHint
@@ -478,8 +550,9 @@ func createResource(schema *openapi.Schema, typeFetcher *TypeFetcher, overrides
 
 func readVirtualField(vf VirtualFieldDetails) Property {
 	prop := Property{
-		title: vf.Name,
-		Type:  Type{&openapi.Schema{Type: vf.Type}},
+		title:       vf.Name,
+		Type:        Type{&openapi.Schema{Type: vf.Type}},
+		Description: vf.Description,
 	}
 
 	if vf.Type == "boolean" {
@@ -0,0 +1,276 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+// 
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serializable
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tpgtools/naming"
+	"gopkg.in/yaml.v2"
+)
+
+// versions are the provider versions tpgtools generates, in order of
+// stability. GA specs have no version subdirectory.
+var versions = []string{"ga", "beta"}
+
+// Resource describes a resource generated by tpgtools for a single location.
+// A spec split into multiple locations produces a Resource per location.
+type Resource struct {
+	// Service is the name of the service directory, such as "compute".
+	Service string
+	// Name is the name of the spec file, such as "firewall".
+	Name string
+	// Location is the location this resource is generated for, or empty if the
+	// spec is not split by location.
+	Location string
+	// Versions holds the generation details for each version the resource is
+	// available at, in order of stability.
+	Versions []*ResourceVersion
+}
+
+// ResourceVersion describes a resource at a single version.
+type ResourceVersion struct {
+	// Version is the provider version, such as "ga" or "beta".
+	Version string
+	// TerraformName is the resource type used in HCL configuration, such as
+	// "google_compute_firewall".
+	TerraformName string
+	// ID is the pattern string of the Terraform resource's id.
+	ID string
+	// ImportFormats are the pattern strings accepted when importing the
+	// resource, most specific first.
+	ImportFormats []string
+	// HasSweeper says if the resource has a generated sweeper.
+	HasSweeper bool
+	// Overrides are the override types applied to the resource or any of its
+	// fields, sorted.
+	Overrides []string
+}
+
+// Inventory returns every resource tpgtools generates from the specs under
+// pathPrefix/api, taking into account the overrides under pathPrefix/overrides.
+func Inventory(pathPrefix string) ([]*Resource, error) {
+	services, err := ioutil.ReadDir(filepath.Join(pathPrefix, "api"))
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*Resource
+	for _, service := range services {
+		if !service.IsDir() {
+			continue
+		}
+
+		for _, version := range versions {
+			packagePath := service.Name()
+			if version != "ga" {
+				packagePath = filepath.Join(service.Name(), version)
+			}
+
+			matches, err := filepath.Glob(filepath.Join(pathPrefix, "api", packagePath, "*.yaml"))
+			if err != nil {
+				return nil, err
+			}
+			for _, match := range matches {
+				name := strings.TrimSuffix(filepath.Base(match), ".yaml")
+				overridePath := filepath.Join(pathPrefix, "overrides", packagePath, filepath.Base(match))
+				rvs, err := inventoryVersion(match, overridePath, service.Name(), version)
+				if err != nil {
+					return nil, err
+				}
+
+				for location, rv := range rvs {
+					r := findResource(resources, service.Name(), name, location)
+					if r == nil {
+						r = &Resource{Service: service.Name(), Name: name, Location: location}
+						resources = append(resources, r)
+					}
+					r.Versions = append(r.Versions, rv)
+				}
+			}
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		l, r := resources[i], resources[j]
+		if l.Service != r.Service {
+			return l.Service < r.Service
+		}
+		if l.Name != r.Name {
+			return l.Name < r.Name
+		}
+		return l.Location < r.Location
+	})
+	return resources, nil
+}
+
+func findResource(resources []*Resource, service, name, location string) *Resource {
+	for _, r := range resources {
+		if r.Service == service && r.Name == name && r.Location == location {
+			return r
+		}
+	}
+	return nil
+}
+
+// spec is the subset of a DCL OpenAPI spec needed to describe a resource.
+type spec struct {
+	Info struct {
+		Title string
+	}
+	Components struct {
+		Schemas map[string]map[string]interface{}
+	}
+}
+
+// override is a single entry of an override file.
+type override struct {
+	Type     string
+	Field    *string
+	Details  interface{}
+	Location *string
+}
+
+// inventoryVersion describes the resources generated from the spec at
+// specPath for a single version, keyed by location.
+func inventoryVersion(specPath, overridePath, service, version string) (map[string]*ResourceVersion, error) {
+	b, err := ioutil.ReadFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+	s := spec{}
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", specPath, err)
+	}
+
+	var overrides []override
+	b, err = ioutil.ReadFile(overridePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(b, &overrides); err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", overridePath, err)
+		}
+	}
+
+	titleParts := strings.Split(s.Info.Title, "/")
+	title := titleParts[len(titleParts)-1]
+	schema, ok := s.Components.Schemas[title]
+	if !ok {
+		return nil, fmt.Errorf("could not find document schema for %s in %s", s.Info.Title, specPath)
+	}
+
+	id, ok := schema["x-dcl-id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("malformed or missing x-dcl-id in %s", specPath)
+	}
+
+	// If the schema cannot be split into two or more locations, it's generated
+	// for a single empty location.
+	locations := []string{""}
+	if lRaw, ok := schema["x-dcl-locations"].([]interface{}); ok && len(lRaw) >= 2 {
+		locations = nil
+		for _, l := range lRaw {
+			locations = append(locations, fmt.Sprint(l))
+		}
+	}
+
+	rvs := make(map[string]*ResourceVersion)
+	for _, location := range locations {
+		var applied []override
+		for _, o := range overrides {
+			if o.Location == nil || *o.Location == location {
+				applied = append(applied, o)
+			}
+		}
+
+		rv := &ResourceVersion{
+			Version:    version,
+			ID:         id,
+			HasSweeper: true,
+		}
+
+		// Mirrors the resource naming in tpgtools' createResource.
+		resourceTitle := title
+		if location != "zone" {
+			resourceTitle = location + resourceTitle
+		}
+		resourceTitle = naming.JSONToSnakeCase(resourceTitle)
+
+		types := make(map[string]bool)
+		for _, o := range applied {
+			types[o.Type] = true
+			switch o.Type {
+			case "CUSTOM_RESOURCE_NAME":
+				if t, ok := detail(o, "title"); ok {
+					resourceTitle = naming.JSONToSnakeCase(t)
+				}
+			case "CUSTOM_ID":
+				if i, ok := detail(o, "id"); ok {
+					rv.ID = i
+				}
+			case "NO_SWEEPER":
+				rv.HasSweeper = false
+			}
+		}
+		for _, o := range applied {
+			if o.Type == "CUSTOM_NAME" && o.Field != nil {
+				if n, ok := detail(o, "name"); ok {
+					rv.ID = naming.RenameIDField(rv.ID, *o.Field, n)
+				}
+			}
+		}
+		rv.TerraformName = "google_" + service + "_" + resourceTitle
+
+		rv.ImportFormats = naming.DefaultImportFormats(rv.ID)
+		for _, o := range applied {
+			if o.Type != "IMPORT_FORMAT" {
+				continue
+			}
+			if m, ok := o.Details.(map[interface{}]interface{}); ok {
+				if formats, ok := m["formats"].([]interface{}); ok {
+					rv.ImportFormats = nil
+					for _, f := range formats {
+						rv.ImportFormats = append(rv.ImportFormats, fmt.Sprint(f))
+					}
+				}
+			}
+		}
+
+		for t := range types {
+			rv.Overrides = append(rv.Overrides, t)
+		}
+		sort.Strings(rv.Overrides)
+
+		rvs[location] = rv
+	}
+	return rvs, nil
+}
+
+// detail returns a string value from an override's details.
+func detail(o override, key string) (string, bool) {
+	m, ok := o.Details.(map[interface{}]interface{})
+	if !ok {
+		return "", false
+	}
+	v, ok := m[key].(string)
+	return v, ok
+}
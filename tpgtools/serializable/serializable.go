@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package serializable contains a function that returns the list of resources that tpgtools currently supports.
+// Package serializable contains functions that return the resources that tpgtools currently supports.
 package serializable
 
 import (
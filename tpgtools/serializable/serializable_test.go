@@ -15,6 +15,7 @@
 package serializable
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -30,3 +31,58 @@ func TestListOfResources(t *testing.T) {
 		t.Errorf("expected 1 resource, got: %v", len(services[0].Resources))
 	}
 }
+
+func TestInventory(t *testing.T) {
+	resources, err := Inventory("test_specs")
+	if err != nil {
+		t.Fatalf("received error: %v", err)
+	}
+	if len(resources) != 3 {
+		t.Fatalf("expected 3 resources, got: %v", len(resources))
+	}
+
+	firewall := resources[0]
+	if firewall.Name != "firewall" || firewall.Location != "" {
+		t.Errorf("expected unlocated firewall, got: %v %q", firewall.Name, firewall.Location)
+	}
+	if len(firewall.Versions) != 2 || firewall.Versions[0].Version != "ga" || firewall.Versions[1].Version != "beta" {
+		t.Fatalf("expected firewall at ga and beta, got: %v", firewall.Versions)
+	}
+	ga := firewall.Versions[0]
+	if ga.TerraformName != "google_compute_firewall" {
+		t.Errorf("expected google_compute_firewall, got: %v", ga.TerraformName)
+	}
+	if ga.HasSweeper {
+		t.Errorf("expected NO_SWEEPER to disable the ga sweeper")
+	}
+	if !firewall.Versions[1].HasSweeper {
+		t.Errorf("expected beta sweeper without overrides")
+	}
+	if strings.Join(ga.Overrides, ",") != "CUSTOM_VALIDATION,NO_SWEEPER" {
+		t.Errorf("unexpected overrides: %v", ga.Overrides)
+	}
+	expectedFormats := []string{
+		"projects/{{project}}/global/firewalls/{{name}}",
+		"{{project}}/{{name}}",
+		"{{name}}",
+	}
+	if strings.Join(ga.ImportFormats, " ") != strings.Join(expectedFormats, " ") {
+		t.Errorf("expected import formats %v, got: %v", expectedFormats, ga.ImportFormats)
+	}
+
+	global := resources[1]
+	if global.Location != "global" || len(global.Versions) != 1 {
+		t.Fatalf("expected beta only global forwarding rule, got: %q %v", global.Location, global.Versions)
+	}
+	if v := global.Versions[0]; v.TerraformName != "google_compute_global_forwarding_rule" || v.ID != "projects/{{project}}/global/forwardingRules/{{name}}" {
+		t.Errorf("unexpected global forwarding rule: %v %v", v.TerraformName, v.ID)
+	}
+
+	regional := resources[2]
+	if v := regional.Versions[0]; regional.Location != "region" || v.TerraformName != "google_compute_forwarding_rule" {
+		t.Errorf("unexpected regional forwarding rule: %q %v", regional.Location, v.TerraformName)
+	}
+	if v := regional.Versions[0]; v.ID != "projects/{{project}}/regions/{{region}}/forwardingRules/{{name}}" {
+		t.Errorf("expected the regional CUSTOM_NAME in the id, got: %v", v.ID)
+	}
+}
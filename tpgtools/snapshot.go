@@ -0,0 +1,381 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+// 
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+
+	"github.com/golang/glog"
+)
+
+// Snapshot is a stable, serializable view of the modeled resources for a
+// single version, used to detect breaking changes between generations.
+type Snapshot struct {
+	Version   string             `json:"version"`
+	Resources []ResourceSnapshot `json:"resources"`
+}
+
+// ResourceSnapshot is the serializable view of a Resource.
+type ResourceSnapshot struct {
+	// Name is the Terraform name of the resource.
+	Name          string             `json:"name"`
+	ID            string             `json:"id"`
+	ImportFormats []string           `json:"import_formats"`
+	Properties    []PropertySnapshot `json:"properties"`
+}
+
+// PropertySnapshot is the serializable view of a Property.
+type PropertySnapshot struct {
+	// Path is the override path of the property, which is stable across
+	// custom names.
+	Path       string             `json:"path"`
+	Name       string             `json:"name"`
+	Type       string             `json:"type"`
+	Required   bool               `json:"required,omitempty"`
+	Optional   bool               `json:"optional,omitempty"`
+	Computed   bool               `json:"computed,omitempty"`
+	ForceNew   bool               `json:"force_new,omitempty"`
+	Enum       []string           `json:"enum,omitempty"`
+	Removed    *string            `json:"removed,omitempty"`
+	Deprecated *string            `json:"deprecated,omitempty"`
+	Properties []PropertySnapshot `json:"properties,omitempty"`
+}
+
+func snapshotResources(version Version, resources []*Resource) Snapshot {
+	s := Snapshot{Version: version.V}
+	for _, r := range resources {
+		formats := append([]string{}, r.ImportFormats...)
+		sort.Strings(formats)
+		s.Resources = append(s.Resources, ResourceSnapshot{
+			Name:          r.TerraformName(),
+			ID:            r.ID,
+			ImportFormats: formats,
+			Properties:    newPropertySnapshots(r.Properties),
+		})
+	}
+	sort.Slice(s.Resources, func(i, j int) bool {
+		return s.Resources[i].Name < s.Resources[j].Name
+	})
+	return s
+}
+
+func newPropertySnapshots(props []Property) []PropertySnapshot {
+	var snapshots []PropertySnapshot
+	for _, p := range props {
+		enum := append([]string{}, enumValues(p)...)
+		sort.Strings(enum)
+		snapshots = append(snapshots, PropertySnapshot{
+			Path:       p.overridePath(),
+			Name:       p.Name(),
+			Type:       p.Type.String(),
+			Required:   p.Required,
+			Optional:   p.Optional,
+			Computed:   p.Computed,
+			ForceNew:   p.ForceNew,
+			Enum:       enum,
+			Removed:    p.Removed,
+			Deprecated: p.Deprecated,
+			Properties: newPropertySnapshots(p.Properties),
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Path < snapshots[j].Path
+	})
+	return snapshots
+}
+
+// generateSnapshots writes the snapshot of the resources for each version to
+// the output path.
+func generateSnapshots(resources map[Version][]*Resource) {
+	if oPath == nil || *oPath == "" {
+		glog.Exit("No output path specified. Each version has its own snapshot, so specify one or a version")
+	}
+	for _, version := range allVersions() {
+		generateSnapshot(version, resources[version])
+	}
+}
+
+// generateSnapshot writes the snapshot of the resources for a version.
+func generateSnapshot(version Version, resources []*Resource) {
+	b, err := json.MarshalIndent(snapshotResources(version, resources), "", "  ")
+	if err != nil {
+		glog.Exit(err)
+	}
+
+	if oPath == nil || *oPath == "" {
+		fmt.Printf("%v\n", string(b))
+	} else {
+		err := ioutil.WriteFile(path.Join(*oPath, fmt.Sprintf("snapshot_%s.json", version.V)), b, 0644)
+		if err != nil {
+			glog.Exit(err)
+		}
+	}
+}
+
+func readSnapshot(p string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("error reading snapshot %s: %v", p, err)
+	}
+	return s, nil
+}
+
+// SnapshotChange is a single difference between two snapshots.
+type SnapshotChange struct {
+	Resource string `json:"resource"`
+	// Field is the override path of the changed field, or empty if the change
+	// applies to the resource.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	// Breaking is set when the change is incompatible with existing
+	// configurations or state.
+	Breaking bool `json:"breaking"`
+	// Acknowledged is set for breaking changes to fields marked with a REMOVED
+	// or DEPRECATED override.
+	Acknowledged bool `json:"acknowledged,omitempty"`
+}
+
+// compareSnapshots reports the changes between the snapshots at oldPath and
+// newPath, and exits non-zero if any are breaking and unacknowledged.
+func compareSnapshots(oldPath, newPath string) {
+	old, err := readSnapshot(oldPath)
+	if err != nil {
+		glog.Exit(err)
+	}
+	updated, err := readSnapshot(newPath)
+	if err != nil {
+		glog.Exit(err)
+	}
+
+	changes := diffSnapshots(old, updated)
+	b, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		glog.Exit(err)
+	}
+	fmt.Printf("%v\n", string(b))
+
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking && !c.Acknowledged {
+			breaking++
+		}
+	}
+	if breaking > 0 {
+		glog.Exitf("found %d unacknowledged breaking change(s)", breaking)
+	}
+}
+
+func diffSnapshots(old, updated *Snapshot) []SnapshotChange {
+	changes := make([]SnapshotChange, 0)
+	newResources := make(map[string]ResourceSnapshot)
+	for _, r := range updated.Resources {
+		newResources[r.Name] = r
+	}
+	oldResources := make(map[string]ResourceSnapshot)
+	for _, r := range old.Resources {
+		oldResources[r.Name] = r
+	}
+
+	for _, o := range old.Resources {
+		n, ok := newResources[o.Name]
+		if !ok {
+			changes = append(changes, SnapshotChange{Resource: o.Name, Message: "resource was removed", Breaking: true})
+			continue
+		}
+		changes = append(changes, diffResourceSnapshots(o, n)...)
+	}
+	for _, n := range updated.Resources {
+		if _, ok := oldResources[n.Name]; !ok {
+			changes = append(changes, SnapshotChange{Resource: n.Name, Message: "resource was added"})
+		}
+	}
+	return changes
+}
+
+func diffResourceSnapshots(old, updated ResourceSnapshot) (changes []SnapshotChange) {
+	change := func(breaking bool, format string, a ...interface{}) {
+		changes = append(changes, SnapshotChange{Resource: old.Name, Message: fmt.Sprintf(format, a...), Breaking: breaking})
+	}
+
+	if old.ID != updated.ID {
+		change(true, "id changed from %q to %q", old.ID, updated.ID)
+	}
+	for _, f := range old.ImportFormats {
+		if !stringInSlice(f, updated.ImportFormats) {
+			change(true, "import format %q was removed", f)
+		}
+	}
+	for _, f := range updated.ImportFormats {
+		if !stringInSlice(f, old.ImportFormats) {
+			change(false, "import format %q was added", f)
+		}
+	}
+
+	oldProps := flattenedPropertySnapshots(old.Properties)
+	newProps := flattenedPropertySnapshots(updated.Properties)
+	for _, k := range sortedSnapshotPaths(oldProps) {
+		o := oldProps[k]
+		n, ok := newProps[k]
+		if !ok {
+			msg := "field was removed"
+			if r := renamedPath(k, o, oldProps, newProps); r != "" {
+				msg = fmt.Sprintf("field was removed, possibly renamed to %q", r)
+			}
+			// Fields that completed a deprecation cycle may be removed.
+			changes = append(changes, SnapshotChange{
+				Resource:     old.Name,
+				Field:        k,
+				Message:      msg,
+				Breaking:     true,
+				Acknowledged: o.Deprecated != nil || o.Removed != nil,
+			})
+			continue
+		}
+		for _, c := range diffPropertySnapshots(o, n) {
+			c.Resource = old.Name
+			c.Field = k
+			c.Acknowledged = c.Breaking && (n.Deprecated != nil || n.Removed != nil)
+			changes = append(changes, c)
+		}
+	}
+	for _, k := range sortedSnapshotPaths(newProps) {
+		if _, ok := oldProps[k]; ok {
+			continue
+		}
+		n := newProps[k]
+		// A new required field breaks existing configurations, unless its parent
+		// is new too.
+		_, parentIsOld := oldProps[parentPath(k)]
+		breaking := n.Required && (parentPath(k) == "" || parentIsOld)
+		changes = append(changes, SnapshotChange{Resource: old.Name, Field: k, Message: "field was added", Breaking: breaking})
+	}
+	return changes
+}
+
+// renamedPath guesses the new path of the removed field at p, as the single
+// field added next to it with the same type. Snapshots can't tell a rename
+// from a removal, so it returns empty unless exactly one field matches.
+func renamedPath(p string, removed PropertySnapshot, oldProps, newProps map[string]PropertySnapshot) string {
+	var candidates []string
+	for k, n := range newProps {
+		if _, ok := oldProps[k]; ok {
+			continue
+		}
+		if parentPath(k) == parentPath(p) && n.Type == removed.Type {
+			candidates = append(candidates, k)
+		}
+	}
+	if len(candidates) != 1 {
+		return ""
+	}
+	return candidates[0]
+}
+
+// diffPropertySnapshots compares the attributes of a single field, without
+// considering its sub-fields.
+func diffPropertySnapshots(old, updated PropertySnapshot) (changes []SnapshotChange) {
+	change := func(breaking bool, format string, a ...interface{}) {
+		changes = append(changes, SnapshotChange{Message: fmt.Sprintf(format, a...), Breaking: breaking})
+	}
+
+	if old.Name != updated.Name {
+		change(true, "name changed from %q to %q", old.Name, updated.Name)
+	}
+	if old.Type != updated.Type {
+		change(true, "type changed from %s to %s", old.Type, updated.Type)
+	}
+	if !old.Required && updated.Required {
+		change(true, "field became required")
+	}
+	if old.Required && !updated.Required {
+		change(false, "field is no longer required")
+	}
+	if (old.Required || old.Optional) && !(updated.Required || updated.Optional) {
+		change(true, "field is no longer settable")
+	}
+	if !(old.Required || old.Optional) && (updated.Required || updated.Optional) {
+		change(false, "field became settable")
+	}
+	if !old.ForceNew && updated.ForceNew {
+		change(true, "field became ForceNew")
+	}
+	if old.ForceNew && !updated.ForceNew {
+		change(false, "field is no longer ForceNew")
+	}
+	// A field that was not an enum accepted any value.
+	if len(old.Enum) > 0 {
+		for _, v := range old.Enum {
+			if !stringInSlice(v, updated.Enum) {
+				change(true, "enum value %q was removed", v)
+			}
+		}
+	}
+	for _, v := range updated.Enum {
+		if len(old.Enum) > 0 && !stringInSlice(v, old.Enum) {
+			change(false, "enum value %q was added", v)
+		}
+	}
+	if len(old.Enum) == 0 && len(updated.Enum) > 0 {
+		change(true, "field became an enum")
+	}
+	if old.Deprecated == nil && updated.Deprecated != nil {
+		change(false, "field was deprecated")
+	}
+	if old.Removed == nil && updated.Removed != nil {
+		change(true, "field was marked removed")
+	}
+	return changes
+}
+
+// flattenedPropertySnapshots returns props and all of their sub-properties
+// keyed by path.
+func flattenedPropertySnapshots(props []PropertySnapshot) map[string]PropertySnapshot {
+	m := make(map[string]PropertySnapshot)
+	for _, p := range props {
+		m[p.Path] = p
+		for k, v := range flattenedPropertySnapshots(p.Properties) {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func sortedSnapshotPaths(m map[string]PropertySnapshot) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parentPath returns the override path of the parent of the field at p, or
+// empty if the field is top-level.
+func parentPath(p string) string {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i] == '.' {
+			return p[:i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,205 @@
+// Copyright 2021 Google LLC. All Rights Reserved.
+// 
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// 
+//     http://www.apache.org/licenses/LICENSE-2.0
+// 
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+)
+
+// VersionDiff describes how a resource differs between the GA and beta
+// versions.
+type VersionDiff struct {
+	// Resource is the Terraform name of the resource.
+	Resource string `json:"resource"`
+
+	// BetaOnly is set when the resource has no GA version. Fields are not
+	// compared for beta only resources.
+	BetaOnly bool `json:"beta_only,omitempty"`
+
+	// BetaOnlyFields are the override paths of fields only present at beta.
+	BetaOnlyFields []string `json:"beta_only_fields,omitempty"`
+
+	// GAOnlyFields are the override paths of fields only present at GA.
+	GAOnlyFields []string `json:"ga_only_fields,omitempty"`
+
+	// ChangedFields are the attributes of fields that differ between versions.
+	ChangedFields []FieldChange `json:"changed_fields,omitempty"`
+
+	// ChangedEnums are the enum fields whose values differ between versions.
+	ChangedEnums []EnumChange `json:"changed_enums,omitempty"`
+}
+
+// FieldChange is a single attribute of a field that differs between versions.
+type FieldChange struct {
+	Field string `json:"field"`
+	// Attribute is one of "type", "required" or "force_new".
+	Attribute string `json:"attribute"`
+	GA        string `json:"ga"`
+	Beta      string `json:"beta"`
+}
+
+// EnumChange lists the values of an enum field only present in one version.
+type EnumChange struct {
+	Field          string   `json:"field"`
+	BetaOnlyValues []string `json:"beta_only_values,omitempty"`
+	GAOnlyValues   []string `json:"ga_only_values,omitempty"`
+}
+
+func (d VersionDiff) empty() bool {
+	return !d.BetaOnly && len(d.BetaOnlyFields) == 0 && len(d.GAOnlyFields) == 0 && len(d.ChangedFields) == 0 && len(d.ChangedEnums) == 0
+}
+
+// generateVersionDiff writes a JSON report of the differences between the GA
+// and beta versions of every resource with a beta version.
+func generateVersionDiff(resources map[Version][]*Resource) {
+	ga := make(map[string]*Resource)
+	for _, r := range resources[GA_VERSION] {
+		ga[r.TerraformName()] = r
+	}
+
+	diffs := make([]VersionDiff, 0)
+	for _, beta := range resources[BETA_VERSION] {
+		d := diffResourceVersions(ga[beta.TerraformName()], beta)
+		if !d.empty() {
+			diffs = append(diffs, d)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Resource < diffs[j].Resource
+	})
+
+	b, err := json.MarshalIndent(diffs, "", "  ")
+	if err != nil {
+		glog.Exit(err)
+	}
+
+	if oPath == nil || *oPath == "" {
+		fmt.Printf("%v\n", string(b))
+	} else {
+		err := ioutil.WriteFile(path.Join(*oPath, "version_diff.json"), b, 0644)
+		if err != nil {
+			glog.Exit(err)
+		}
+	}
+}
+
+// diffResourceVersions compares the GA and beta versions of a resource. ga is
+// nil if the resource is beta only.
+func diffResourceVersions(ga, beta *Resource) VersionDiff {
+	d := VersionDiff{Resource: beta.TerraformName()}
+	if ga == nil {
+		d.BetaOnly = true
+		return d
+	}
+
+	gaProps := flattenedProperties(ga.Properties)
+	betaProps := flattenedProperties(beta.Properties)
+
+	for _, k := range sortedPropertyPaths(betaProps) {
+		b := betaProps[k]
+		g, ok := gaProps[k]
+		if !ok {
+			d.BetaOnlyFields = append(d.BetaOnlyFields, k)
+			continue
+		}
+
+		if g.Type.String() != b.Type.String() {
+			d.ChangedFields = append(d.ChangedFields, FieldChange{Field: k, Attribute: "type", GA: g.Type.String(), Beta: b.Type.String()})
+		}
+		if requiredness(g) != requiredness(b) {
+			d.ChangedFields = append(d.ChangedFields, FieldChange{Field: k, Attribute: "required", GA: requiredness(g), Beta: requiredness(b)})
+		}
+		if g.ForceNew != b.ForceNew {
+			d.ChangedFields = append(d.ChangedFields, FieldChange{Field: k, Attribute: "force_new", GA: strconv.FormatBool(g.ForceNew), Beta: strconv.FormatBool(b.ForceNew)})
+		}
+
+		betaOnlyValues := stringsNotIn(enumValues(b), enumValues(g))
+		gaOnlyValues := stringsNotIn(enumValues(g), enumValues(b))
+		if len(betaOnlyValues) > 0 || len(gaOnlyValues) > 0 {
+			d.ChangedEnums = append(d.ChangedEnums, EnumChange{Field: k, BetaOnlyValues: betaOnlyValues, GAOnlyValues: gaOnlyValues})
+		}
+	}
+
+	for _, k := range sortedPropertyPaths(gaProps) {
+		if _, ok := betaProps[k]; !ok {
+			d.GAOnlyFields = append(d.GAOnlyFields, k)
+		}
+	}
+
+	return d
+}
+
+// flattenedProperties returns props and all of their sub-properties keyed by
+// override path.
+func flattenedProperties(props []Property) map[string]Property {
+	m := make(map[string]Property)
+	for _, p := range props {
+		m[p.overridePath()] = p
+		for k, v := range flattenedProperties(p.Properties) {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func sortedPropertyPaths(m map[string]Property) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// requiredness summarises whether a field is required, optional and/or
+// computed in Terraform.
+func requiredness(p Property) string {
+	switch {
+	case p.Required:
+		return "required"
+	case p.Optional && p.Computed:
+		return "optional+computed"
+	case p.Optional:
+		return "optional"
+	default:
+		return "computed"
+	}
+}
+
+// enumValues returns the enum values of a field, or of its items if the field
+// is an enum array.
+func enumValues(p Property) []string {
+	if p.Type.IsEnumArray() {
+		return p.typ.Items.Enum
+	}
+	return p.typ.Enum
+}
+
+// stringsNotIn returns the values in a that are not in b.
+func stringsNotIn(a, b []string) (values []string) {
+	for _, v := range a {
+		if !stringInSlice(v, b) {
+			values = append(values, v)
+		}
+	}
+	return values
+}